@@ -0,0 +1,153 @@
+package clusters
+
+import (
+	"math/rand"
+	"sync"
+)
+
+type canopyClusterer struct {
+	t1, t2 float64
+
+	distance DistanceFunc
+
+	// slices holding indices of members of each canopy, points chosen as the centers of canopies and
+	// means of canopies. Access is synchronized to avoid read during computation.
+	mu sync.RWMutex
+	c  [][]int
+	p  [][]float64
+	m  [][]float64
+
+	// dataset
+	d [][]float64
+}
+
+// Implementation of canopy clustering ("Efficient Clustering of High-Dimensional Data Sets with Application to Reference Matching",
+// McCallum et al.). Each canopy consists of all points closer than the loose threshold t1 to its center, while points closer
+// than the tight threshold t2 can no longer become centers. Since the distance is evaluated often, it should be cheap to compute.
+// The result can be used as an Index narrowing down the search for neighbours in DBSCAN and OPTICS or to seed KMeans.
+// Neighbours found this way are exact only if t1 >= t2 + eps, otherwise some of them may be silently missed.
+func Canopy(t1, t2 float64, distance DistanceFunc) (CanopyClusterer, error) {
+	if t2 <= 0 {
+		return nil, errZeroThreshold
+	}
+
+	if t1 < t2 {
+		return nil, errInvalidThresholds
+	}
+
+	var d DistanceFunc
+	{
+		if distance != nil {
+			d = distance
+		} else {
+			d = EuclideanDistance
+		}
+	}
+
+	return &canopyClusterer{
+		t1:       t1,
+		t2:       t2,
+		distance: d,
+	}, nil
+}
+
+func (c *canopyClusterer) Learn(data [][]float64) error {
+	if len(data) == 0 {
+		return errEmptySet
+	}
+
+	c.mu.Lock()
+
+	c.d = data
+
+	c.c = make([][]int, 0)
+	c.p = make([][]float64, 0)
+	c.m = make([][]float64, 0)
+
+	var (
+		d float64
+		r = make([]bool, len(data))
+	)
+
+	for _, i := range rand.Perm(len(data)) {
+		if r[i] {
+			continue
+		}
+
+		var (
+			ns = make([]int, 0)
+			m  = make([]float64, len(data[i]))
+		)
+
+		for j := 0; j < len(data); j++ {
+			if d = c.distance(data[i], data[j]); d < c.t1 {
+				ns = append(ns, j)
+
+				for k := 0; k < len(m); k++ {
+					m[k] += data[j][k]
+				}
+
+				if d < c.t2 {
+					r[j] = true
+				}
+			}
+		}
+
+		for k := 0; k < len(m); k++ {
+			m[k] /= float64(len(ns))
+		}
+
+		c.c = append(c.c, ns)
+		c.p = append(c.p, data[i])
+		c.m = append(c.m, m)
+	}
+
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *canopyClusterer) Build(data [][]float64) error {
+	return c.Learn(data)
+}
+
+func (c *canopyClusterer) Canopies() [][]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.c
+}
+
+func (c *canopyClusterer) Centers() [][]float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.m
+}
+
+// Members of all canopies whose centers are closer than t1 to the observation. Since every data point is closer than t2
+// to the center of some canopy it belongs to, all points closer than t1 - t2 to the observation are returned.
+func (c *canopyClusterer) Query(p []float64) []int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var (
+		r = make([]int, 0)
+		v = make(map[int]bool)
+	)
+
+	for i := 0; i < len(c.p); i++ {
+		if c.distance(p, c.p[i]) >= c.t1 {
+			continue
+		}
+
+		for _, j := range c.c[i] {
+			if !v[j] {
+				v[j] = true
+				r = append(r, j)
+			}
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,148 @@
+package clusters
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCanopyCoversDataset(t *testing.T) {
+	var d = blobs(2, 500, 0.5)
+
+	c, e := Canopy(3, 2, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing canopy clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	var v = make([]bool, len(d))
+
+	for _, ns := range c.Canopies() {
+		for _, i := range ns {
+			v[i] = true
+		}
+	}
+
+	for i := 0; i < len(v); i++ {
+		if !v[i] {
+			t.Errorf("Data point %d does not belong to any canopy\n", i)
+		}
+	}
+
+	if len(c.Canopies()) != len(c.Centers()) {
+		t.Errorf("Number of canopies and centers does not match: %d vs %d\n", len(c.Canopies()), len(c.Centers()))
+	}
+}
+
+func TestKMeansWithCanopyClusterNumberMatches(t *testing.T) {
+	const (
+		C = 2
+	)
+
+	var d = blobs(C, 500, 0.5)
+
+	p, e := Canopy(8, 6, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing canopy clusterer: %s\n", e.Error())
+	}
+
+	c, e := KMeansWithCanopy(1000, p, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing kmeans clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if len(c.Sizes()) != C {
+		t.Errorf("Number of clusters does not match: %d vs %d\n", len(c.Sizes()), C)
+	}
+}
+
+func TestKMeansWithSingleCanopy(t *testing.T) {
+	var d = [][]float64{{0, 0}, {1, 0}, {0, 1}}
+
+	p, e := Canopy(100, 50, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing canopy clusterer: %s\n", e.Error())
+	}
+
+	c, e := KMeansWithCanopy(1000, p, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing kmeans clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != errOneCluster {
+		t.Errorf("Learning from a single canopy should fail with: %v\n", errOneCluster)
+	}
+}
+
+func TestDBSCANWithCanopyClusterNumberMatches(t *testing.T) {
+	const (
+		C = 2
+	)
+
+	var d = blobs(C, 500, 0.5)
+
+	p, e := Canopy(3, 2, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing canopy clusterer: %s\n", e.Error())
+	}
+
+	c, e := DBSCANWithIndex(5, 0.5, 0, EuclideanDistance, p)
+	if e != nil {
+		t.Errorf("Error initializing dbscan clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if len(c.Sizes()) != C {
+		t.Errorf("Number of clusters does not match: %d vs %d\n", len(c.Sizes()), C)
+	}
+}
+
+func TestOPTICSWithCanopyClusterNumberMatches(t *testing.T) {
+	const (
+		C = 2
+	)
+
+	var d = blobs(C, 500, 0.5)
+
+	p, e := Canopy(3, 2, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing canopy clusterer: %s\n", e.Error())
+	}
+
+	c, e := OPTICSWithIndex(20, 1, 0.3, 0, EuclideanDistance, p)
+	if e != nil {
+		t.Errorf("Error initializing optics clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if len(c.Sizes()) != C {
+		t.Errorf("Number of clusters does not match: %d vs %d\n", len(c.Sizes()), C)
+	}
+}
+
+// Gaussian blobs centered along the diagonal, with points of consecutive blobs interleaved
+func blobs(n, size int, sd float64) [][]float64 {
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, n*size)
+	)
+
+	for i := 0; i < len(d); i++ {
+		o := float64(10 * (i % n))
+		d[i] = []float64{o + r.NormFloat64()*sd, o + r.NormFloat64()*sd}
+	}
+
+	return d
+}
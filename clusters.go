@@ -24,6 +24,14 @@ type HCEvent struct {
 	Observation []float64
 }
 
+// ICEvent represents a change of cluster membership of a data point caused by insertion or deletion of a point
+// during incremental learning. Merges of clusters are reported as points moving to an existing cluster, while splits
+// as points moving to a newly created one. Deleted points are moved to cluster 0.
+type ICEvent struct {
+	Index, Previous, Cluster int
+	Observation              []float64
+}
+
 // Clusterer defines the operation of learning
 // common for all algorithms
 type Clusterer interface {
@@ -57,6 +65,79 @@ type HardClusterer interface {
 	Clusterer
 }
 
+// StreamClusterer defines a set of operations for hard clustering algorithms which summarise an evolving
+// data stream in bounded memory
+type StreamClusterer interface {
+
+	// Snapshot computes the macro clustering of the current state of the stream and returns centers of the clusters
+	Snapshot() [][]float64
+
+	// Implement common operations
+	HardClusterer
+}
+
+// IncrementalClusterer defines a set of operations for hard clustering algorithms which maintain the clustering
+// while data points are inserted and deleted
+type IncrementalClusterer interface {
+
+	// Insert adds the observation to the dataset, returning its index and changes of cluster membership it caused
+	Insert(observation []float64) (int, []*ICEvent)
+
+	// Delete removes the data point with given index from the dataset, returning changes of cluster membership it caused.
+	// Indices of remaining data points do not change, while those of deleted ones are reused by later insertions.
+	Delete(index int) ([]*ICEvent, error)
+
+	// Implement common operations
+	HardClusterer
+}
+
+// MatrixClusterer defines a set of operations for hard clustering algorithms which can learn from precomputed
+// distances between data points
+type MatrixClusterer interface {
+
+	// LearnMatrix trains the algorithm using the distance matrix instead of the dataset
+	LearnMatrix(DistanceMatrix) error
+
+	// Implement common operations
+	HardClusterer
+}
+
+// DistanceMatrix represents precomputed distances between data points
+type DistanceMatrix interface {
+
+	// Len returns the number of data points
+	Len() int
+
+	// At returns the distance between data points i and j
+	At(i, j int) float64
+}
+
+// CanopyClusterer defines a set of operations for canopy clustering, which divides the dataset into overlapping canopies
+type CanopyClusterer interface {
+
+	// Canopies returns indices of data points belonging to respective canopies
+	Canopies() [][]int
+
+	// Centers returns means of respective canopies
+	Centers() [][]float64
+
+	// Implement common operation
+	Clusterer
+
+	// Canopies can be used to narrow down the search for neighbours
+	Index
+}
+
+// Index defines a structure used to narrow down the search for neighbours of an observation in the dataset
+type Index interface {
+
+	// Build indexes the dataset
+	Build([][]float64) error
+
+	// Query returns indices of data points which are candidates for neighbours of the observation
+	Query(observation []float64) []int
+}
+
 // Estimator defines a computation used to determine an optimal number of clusters in the dataset
 type Estimator interface {
 
@@ -64,6 +145,63 @@ type Estimator interface {
 	Estimate([][]float64) (int, error)
 }
 
+// ScoringEstimator defines an estimator which also reports how well the dataset is clustered for every examined number of clusters
+type ScoringEstimator interface {
+
+	// Scores returns the score of every number of clusters examined during the last estimation, starting with the lowest one
+	Scores() []float64
+
+	// Implement common operation
+	Estimator
+}
+
+// EpsEstimator defines a computation used to determine eps of density based algorithms such as DBSCAN and OPTICS
+type EpsEstimator interface {
+
+	// Estimate provides a suggested eps for the dataset
+	Estimate([][]float64) (float64, error)
+
+	// Curve returns the values examined during the last estimation, e.g. to be plotted
+	Curve() []float64
+}
+
+// IntervalEstimator defines a scoring estimator which also reports confidence intervals of the scores
+type IntervalEstimator interface {
+
+	// Intervals returns lower and upper bounds of 95% confidence intervals of respective scores
+	Intervals() [][2]float64
+
+	// Implement common operation
+	ScoringEstimator
+}
+
+// GapEstimator defines an estimator using gap statistic which also reports its diagnostics
+type GapEstimator interface {
+
+	// Statistics returns the gap statistic of every examined number of clusters, starting with 1
+	Statistics() []GapStatistic
+
+	// Implement common operation
+	Estimator
+}
+
+// GapStatistic holds diagnostics of the gap statistic for a single number of clusters
+type GapStatistic struct {
+	Clusters int
+
+	// Gap is the difference between expected logarithm of within-cluster dispersion of reference datasets and LogWk
+	Gap float64
+
+	// Sk is the standard deviation of logarithms of dispersion of reference datasets, accounting for simulation error
+	Sk float64
+
+	// LogWk is the logarithm of within-cluster dispersion of the dataset
+	LogWk float64
+}
+
+// ClustererFactory creates a clusterer dividing the dataset into given number of clusters, e.g. KMeans
+type ClustererFactory func(clusters int) (HardClusterer, error)
+
 // Importer defines an operation of importing the dataset from an external file
 type Importer interface {
 
@@ -2,6 +2,7 @@ package clusters
 
 import (
 	"container/heap"
+	"math"
 	"math/rand"
 	"sync"
 )
@@ -30,7 +31,7 @@ func newPriorityQueue(size int) priorityQueue {
 func (pq priorityQueue) Len() int { return len(pq) }
 
 func (pq priorityQueue) Less(i, j int) bool {
-	return pq[i].p > pq[j].p
+	return pq[i].p < pq[j].p
 }
 
 func (pq priorityQueue) Swap(i, j int) {
@@ -66,6 +67,28 @@ func (pq *priorityQueue) Update(item *pItem, value int, priority float64) {
 	heap.Fix(pq, item.i)
 }
 
+// Number of workers scanning a dataset of size l concurrently, growing with its order of magnitude and capped
+// by the requested number unless it is 0
+func numWorkers(l, workers int) int {
+	var b int
+
+	if l < 1000 {
+		b = 1
+	} else if l < 10000 {
+		b = 10
+	} else if l < 100000 {
+		b = 100
+	} else {
+		b = 1000
+	}
+
+	if workers == 0 || workers > b {
+		return b
+	}
+
+	return workers
+}
+
 func bounds(data [][]float64) []*[2]float64 {
 	var (
 		wg sync.WaitGroup
@@ -105,3 +128,47 @@ func bounds(data [][]float64) []*[2]float64 {
 func uniform(data *[2]float64) float64 {
 	return rand.Float64()*(data[1]-data[0]) + data[0]
 }
+
+/* Index of the knee of the convex curve y sampled at evenly spaced points, found with the Kneedle algorithm
+ * ("Finding a "Kneedle" in a Haystack: Detecting Knee Points in System Behavior", Satopaa et al.) in its offline form,
+ * that is the point of the normalized curve furthest from the chord, which is robust to noise of long curves.
+ * The curve is decreasing (e.g. inertia of k-means) or increasing (e.g. sorted distances to k-th nearest neighbour) */
+func knee(y []float64, decreasing bool) int {
+	if len(y) < 3 {
+		return 0
+	}
+
+	var (
+		l      = len(y)
+		lo, hi = y[0], y[0]
+		m      int
+		d, b   float64
+	)
+
+	for i := 1; i < l; i++ {
+		lo = math.Min(lo, y[i])
+		hi = math.Max(hi, y[i])
+	}
+
+	if hi == lo {
+		return 0
+	}
+
+	// difference between the chord and the normalized curve
+	for i := 0; i < l; i++ {
+		x, v := float64(i)/float64(l-1), (y[i]-lo)/(hi-lo)
+
+		if decreasing {
+			d = 1 - x - v
+		} else {
+			d = x - v
+		}
+
+		if d > b {
+			b = d
+			m = i
+		}
+	}
+
+	return m
+}
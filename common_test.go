@@ -1,6 +1,7 @@
 package clusters
 
 import (
+	"container/heap"
 	"testing"
 )
 
@@ -41,11 +42,11 @@ func TestQueueReturnsInPriorityOrder(t *testing.T) {
 	queue.Push(itemTwo)
 	queue.Push(itemOne)
 
-	if queue.Pop().(*pItem) != itemOne {
+	if heap.Pop(&queue).(*pItem) != itemOne {
 		t.Error("Queue is should return itemOne first")
 	}
 
-	if queue.Pop().(*pItem) != itemTwo {
+	if heap.Pop(&queue).(*pItem) != itemTwo {
 		t.Error("Queue is should return itemTwo next")
 	}
 
@@ -54,6 +55,26 @@ func TestQueueReturnsInPriorityOrder(t *testing.T) {
 	}
 }
 
+func TestQueueReturnsManyItemsInPriorityOrder(t *testing.T) {
+	var (
+		queue = newPriorityQueue(5)
+		p     = []float64{0.3, 0.1, 0.5, 0.4, 0.2}
+	)
+
+	for i := 0; i < len(p); i++ {
+		queue.Push(&pItem{
+			v: i,
+			p: p[i],
+		})
+	}
+
+	for i := 1; i <= len(p); i++ {
+		if item := heap.Pop(&queue).(*pItem); item.p != float64(i)/10 {
+			t.Errorf("Queue should return item of priority %f, not %f\n", float64(i)/10, item.p)
+		}
+	}
+}
+
 func TestQueueReturnsInPriorityOrderAfterUpdate(t *testing.T) {
 	queue := newPriorityQueue(2)
 
@@ -73,11 +94,11 @@ func TestQueueReturnsInPriorityOrderAfterUpdate(t *testing.T) {
 
 	queue.Update(itemTwo, 1, 0.4)
 
-	if queue.Pop().(*pItem) != itemTwo {
+	if heap.Pop(&queue).(*pItem) != itemTwo {
 		t.Error("Queue is should return itemTwo first")
 	}
 
-	if queue.Pop().(*pItem) != itemOne {
+	if heap.Pop(&queue).(*pItem) != itemOne {
 		t.Error("Queue is should return itemOne next")
 	}
 
@@ -133,3 +154,19 @@ func TestUniform(t *testing.T) {
 		}
 	}
 }
+
+func TestKneeOfDecreasingCurve(t *testing.T) {
+	var y = []float64{100, 40, 10, 9, 8, 7, 6, 5, 4, 3}
+
+	if k := knee(y, true); k != 2 {
+		t.Errorf("Knee should be at 2, it is at %d\n", k)
+	}
+}
+
+func TestKneeOfIncreasingCurve(t *testing.T) {
+	var y = []float64{1, 1.1, 1.2, 1.3, 1.4, 1.5, 1.6, 3, 8, 20}
+
+	if k := knee(y, false); k != 7 {
+		t.Errorf("Knee should be at 7, it is at %d\n", k)
+	}
+}
@@ -0,0 +1,383 @@
+package clusters
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+const (
+	// factor q by which the number of points of every partition is reduced by partial clustering
+	cureReduction = 3
+)
+
+// cluster built during hierarchical merging in CURE
+type cureCluster struct {
+	// indices of members, mean and shrunk representative points
+	p []int
+	m []float64
+	r [][]float64
+
+	// closest cluster and distance to it
+	c *cureCluster
+	e float64
+
+	// item of the cluster in the queue ordered by distance to the closest cluster
+	q *pItem
+}
+
+type cureClusterer struct {
+	number, representatives, sample, partitions int
+	alpha                                       float64
+
+	distance DistanceFunc
+
+	// slices holding the cluster mapping and sizes. Access is synchronized to avoid read during computation.
+	mu   sync.RWMutex
+	a, b []int
+
+	// representative points of each cluster
+	r [][][]float64
+
+	// dataset
+	d [][]float64
+}
+
+// Implementation of CURE algorithm ("CURE: An Efficient Clustering Algorithm for Large Databases", Guha et al.).
+// Each cluster is represented by a number of well scattered points shrunk towards its mean by the factor of alpha,
+// which allows for discovering clusters of non-spherical shapes. For large datasets hierarchical clustering is run
+// on a random sample of given size, which is first divided into partitions clustered independently. Passing 0 as sample
+// will result in using the whole dataset, while passing 0 or 1 as partitions disables partitioning. Merging n clusters
+// takes O(n^2 log n) time when they are well separated and up to O(n^3) otherwise, so large datasets should be sampled.
+func CURE(clusters, representatives int, alpha float64, sample, partitions int, distance DistanceFunc) (HardClusterer, error) {
+	if clusters < 2 {
+		return nil, errOneCluster
+	}
+
+	if representatives < 1 {
+		return nil, errZeroRepresentatives
+	}
+
+	if alpha < 0 || alpha > 1 {
+		return nil, errInvalidAlpha
+	}
+
+	if sample < 0 {
+		return nil, errInvalidSample
+	}
+
+	if partitions < 0 {
+		return nil, errInvalidPartitions
+	}
+
+	var d DistanceFunc
+	{
+		if distance != nil {
+			d = distance
+		} else {
+			d = EuclideanDistance
+		}
+	}
+
+	return &cureClusterer{
+		number:          clusters,
+		representatives: representatives,
+		alpha:           alpha,
+		sample:          sample,
+		partitions:      partitions,
+		distance:        d,
+	}, nil
+}
+
+func (c *cureClusterer) IsOnline() bool {
+	return false
+}
+
+func (c *cureClusterer) WithOnline(o Online) HardClusterer {
+	return c
+}
+
+func (c *cureClusterer) Learn(data [][]float64) error {
+	if len(data) == 0 {
+		return errEmptySet
+	}
+
+	c.mu.Lock()
+
+	c.d = data
+
+	var (
+		s  = rand.Perm(len(data))
+		p  = 1
+		cs = make([]*cureCluster, 0)
+	)
+
+	if c.sample > 0 && c.sample < len(s) {
+		s = s[:c.sample]
+	}
+
+	if c.partitions > 1 {
+		p = c.partitions
+	}
+
+	// each partition is reduced independently before clustering the partial clusters together
+	for i := 0; i < p; i++ {
+		var (
+			pcs = make([]*cureCluster, 0)
+			ps  = s[i*len(s)/p : (i+1)*len(s)/p]
+		)
+
+		for _, j := range ps {
+			pcs = append(pcs, &cureCluster{
+				p: []int{j},
+				m: data[j],
+				r: [][]float64{data[j]},
+			})
+		}
+
+		// every partition holds n/p points and is reduced to n/(pq) clusters
+		if p > 1 {
+			pcs = c.reduce(pcs, int(math.Max(float64(c.number), float64(len(ps)/cureReduction))))
+		}
+
+		cs = append(cs, pcs...)
+	}
+
+	cs = c.reduce(cs, c.number)
+
+	c.r = make([][][]float64, len(cs))
+	for i := 0; i < len(cs); i++ {
+		c.r[i] = cs[i].r
+	}
+
+	c.a = make([]int, len(data))
+	c.b = make([]int, len(cs))
+
+	for i := 0; i < len(data); i++ {
+		c.a[i] = c.predict(data[i]) + 1
+		c.b[c.a[i]-1]++
+	}
+
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *cureClusterer) Sizes() []int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.b
+}
+
+func (c *cureClusterer) Guesses() []int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.a
+}
+
+func (c *cureClusterer) Predict(p []float64) int {
+	return c.predict(p)
+}
+
+func (c *cureClusterer) Online(observations chan []float64, done chan struct{}) chan *HCEvent {
+	return nil
+}
+
+// private
+func (c *cureClusterer) predict(p []float64) int {
+	var (
+		l int
+		d float64
+		m float64 = math.Inf(1)
+	)
+
+	for i := 0; i < len(c.r); i++ {
+		for j := 0; j < len(c.r[i]); j++ {
+			if d = c.distance(p, c.r[i][j]); d < m {
+				m = d
+				l = i
+			}
+		}
+	}
+
+	return l
+}
+
+/* Merge the closest pair of clusters until k remain. Clusters are kept in a queue ordered by distance to their closest
+ * cluster, which needs to be recomputed only for clusters whose closest one was merged and is farther than the merged one */
+func (c *cureClusterer) reduce(cs []*cureCluster, k int) []*cureCluster {
+	var (
+		q = newPriorityQueue(len(cs))
+		u = make([]*cureCluster, 0, 2*len(cs))
+	)
+
+	for i := 0; i < len(cs); i++ {
+		c.closest(cs[i], cs)
+
+		cs[i].q = &pItem{v: len(u), p: cs[i].e}
+		u = append(u, cs[i])
+
+		heap.Push(&q, cs[i].q)
+	}
+
+	for len(cs) > k {
+		var (
+			x = u[heap.Pop(&q).(*pItem).v]
+			y = x.c
+			w = c.merge(x, y)
+		)
+
+		heap.Remove(&q, y.q.i)
+
+		for i := 0; i < len(cs); i++ {
+			if cs[i] == x {
+				cs[i] = w
+				break
+			}
+		}
+
+		for i := 0; i < len(cs); i++ {
+			if cs[i] == y {
+				cs = append(cs[:i], cs[i+1:]...)
+				break
+			}
+		}
+
+		w.e = math.Inf(1)
+
+		for i := 0; i < len(cs); i++ {
+			if cs[i] == w {
+				continue
+			}
+
+			d := c.clusterDistance(cs[i], w)
+
+			if d < w.e {
+				w.c = cs[i]
+				w.e = d
+			}
+
+			if cs[i].c == x || cs[i].c == y {
+				if d <= cs[i].e {
+					cs[i].c = w
+					cs[i].e = d
+				} else {
+					c.closest(cs[i], cs)
+				}
+			} else if d < cs[i].e {
+				cs[i].c = w
+				cs[i].e = d
+			}
+
+			if cs[i].q.p != cs[i].e {
+				q.Update(cs[i].q, cs[i].q.v, cs[i].e)
+			}
+		}
+
+		w.q = &pItem{v: len(u), p: w.e}
+		u = append(u, w)
+
+		heap.Push(&q, w.q)
+	}
+
+	return cs
+}
+
+func (c *cureClusterer) closest(x *cureCluster, cs []*cureCluster) {
+	var d float64
+
+	x.c = nil
+	x.e = math.Inf(1)
+
+	for i := 0; i < len(cs); i++ {
+		if cs[i] == x {
+			continue
+		}
+
+		if d = c.clusterDistance(x, cs[i]); d < x.e {
+			x.c = cs[i]
+			x.e = d
+		}
+	}
+}
+
+func (c *cureClusterer) clusterDistance(x, y *cureCluster) float64 {
+	var d, m float64 = 0, math.Inf(1)
+
+	for i := 0; i < len(x.r); i++ {
+		for j := 0; j < len(y.r); j++ {
+			if d = c.distance(x.r[i], y.r[j]); d < m {
+				m = d
+			}
+		}
+	}
+
+	return m
+}
+
+func (c *cureClusterer) merge(x, y *cureCluster) *cureCluster {
+	var (
+		l = len(x.p) + len(y.p)
+		w = &cureCluster{
+			p: make([]int, 0, l),
+			m: make([]float64, len(x.m)),
+			r: make([][]float64, 0, c.representatives),
+		}
+		t = make([][]float64, 0, c.representatives)
+		v = make([]bool, l)
+	)
+
+	w.p = append(append(w.p, x.p...), y.p...)
+
+	for i := 0; i < len(w.m); i++ {
+		w.m[i] = (float64(len(x.p))*x.m[i] + float64(len(y.p))*y.m[i]) / float64(l)
+	}
+
+	// choose well scattered points, the first one farthest from the mean and every next one farthest from those already chosen
+	for len(t) < c.representatives && len(t) < l {
+		var (
+			k    = -1
+			m, d float64
+		)
+
+		for i := 0; i < l; i++ {
+			if v[i] {
+				continue
+			}
+
+			if len(t) == 0 {
+				d = c.distance(c.d[w.p[i]], w.m)
+			} else {
+				d = math.Inf(1)
+
+				for j := 0; j < len(t); j++ {
+					d = math.Min(d, c.distance(c.d[w.p[i]], t[j]))
+				}
+			}
+
+			if k == -1 || d > m {
+				k = i
+				m = d
+			}
+		}
+
+		v[k] = true
+		t = append(t, c.d[w.p[k]])
+	}
+
+	// shrink the scattered points towards the mean
+	for i := 0; i < len(t); i++ {
+		r := make([]float64, len(t[i]))
+
+		for j := 0; j < len(r); j++ {
+			r[j] = t[i][j] + c.alpha*(w.m[j]-t[i][j])
+		}
+
+		w.r = append(w.r, r)
+	}
+
+	return w
+}
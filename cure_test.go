@@ -0,0 +1,40 @@
+package clusters
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCUREFindsElongatedClusters(t *testing.T) {
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 400)
+	)
+
+	// two long parallel segments, which k-means would cut across
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{r.Float64() * 20, float64(3*(i%2)) + r.NormFloat64()*0.1}
+	}
+
+	c, e := CURE(2, 10, 0.05, 200, 2, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing cure clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	var g = c.Guesses()
+
+	for i := 2; i < len(g); i++ {
+		if g[i] != g[i%2] {
+			t.Errorf("Data point %d assigned to a wrong cluster\n", i)
+			break
+		}
+	}
+
+	if g[0] == g[1] {
+		t.Error("Segments should be assigned to different clusters")
+	}
+}
@@ -10,6 +10,21 @@ type dbscanClusterer struct {
 
 	distance DistanceFunc
 
+	// For spatio-temporal clustering only
+	column   int
+	teps     float64
+	temporal DistanceFunc
+
+	// index narrowing down the search for nearest neighbours, if used
+	index Index
+
+	// index actually used during learning and prediction: either the one above or the grid chosen automatically
+	nn Index
+
+	// precomputed distances between data points, if used
+	precomputed bool
+	dm          DistanceMatrix
+
 	// slices holding the cluster mapping and sizes. Access is synchronized to avoid read during computation.
 	mu   sync.RWMutex
 	a, b []int
@@ -20,7 +35,7 @@ type dbscanClusterer struct {
 	m          *sync.Mutex
 	w          *sync.WaitGroup
 	r          *[]int
-	p          []float64
+	p          int
 
 	// visited points
 	v []bool
@@ -30,7 +45,8 @@ type dbscanClusterer struct {
 }
 
 // Implementation of DBSCAN algorithm with concurrent nearest neighbour computation. The number of goroutines acting concurrently
-// is controlled via workers argument. Passing 0 will result in this number being chosen arbitrarily.
+// is controlled via workers argument. Passing 0 will result in this number being chosen arbitrarily. Eps is expressed
+// in the units of the distance function, e.g. in metres for HaversineDistance(LatLon, Metres).
 func DBSCAN(minpts int, eps float64, workers int, distance DistanceFunc) (HardClusterer, error) {
 	if minpts < 1 {
 		return nil, errZeroMinpts
@@ -61,6 +77,38 @@ func DBSCAN(minpts int, eps float64, workers int, distance DistanceFunc) (HardCl
 	}, nil
 }
 
+// Implementation of DBSCAN algorithm which searches for nearest neighbours only among the candidates returned by the index,
+// e.g. members of shared canopies. The index is built from the dataset during learning.
+func DBSCANWithIndex(minpts int, eps float64, workers int, distance DistanceFunc, index Index) (HardClusterer, error) {
+	if index == nil {
+		return nil, errNilIndex
+	}
+
+	c, e := DBSCAN(minpts, eps, workers, distance)
+	if e != nil {
+		return nil, e
+	}
+
+	c.(*dbscanClusterer).index = index
+
+	return c, nil
+}
+
+// Implementation of DBSCAN algorithm which learns from precomputed distances between data points, e.g. distances along
+// the road network. Learn expects the dataset to be the square distance matrix, while Predict expects the observation
+// to hold distances to every data point.
+func PrecomputedDBSCAN(minpts int, eps float64, workers int) (MatrixClusterer, error) {
+	c, e := DBSCAN(minpts, eps, workers, nil)
+	if e != nil {
+		return nil, e
+	}
+
+	d := c.(*dbscanClusterer)
+	d.precomputed = true
+
+	return d, nil
+}
+
 func (c *dbscanClusterer) IsOnline() bool {
 	return false
 }
@@ -70,33 +118,67 @@ func (c *dbscanClusterer) WithOnline(o Online) HardClusterer {
 }
 
 func (c *dbscanClusterer) Learn(data [][]float64) error {
+	if c.precomputed {
+		m, e := SquareDistanceMatrix(data)
+		if e != nil {
+			return e
+		}
+
+		return c.LearnMatrix(m)
+	}
+
 	if len(data) == 0 {
 		return errEmptySet
 	}
 
+	// ST-DBSCAN needs at least one temporal feature
+	if c.temporal != nil && c.column >= len(data[0]) {
+		return errColumnOutOfRange
+	}
+
 	c.mu.Lock()
 
-	c.l = len(data)
-	c.s = c.numWorkers()
-	c.o = c.s - 1
-	c.f = c.l / c.s
+	c.nn = c.index
+
+	if c.nn == nil && useGrid(data, c.distance) {
+		c.nn = &gridIndex{size: c.eps}
+	}
+
+	if c.nn != nil {
+		if e := c.nn.Build(data); e != nil {
+			c.mu.Unlock()
+			return e
+		}
+	}
 
 	c.d = data
+	c.dm = nil
+	c.l = len(data)
 
-	c.v = make([]bool, c.l)
+	c.learn()
 
-	c.a = make([]int, c.l)
-	c.b = make([]int, 0)
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *dbscanClusterer) LearnMatrix(m DistanceMatrix) error {
+	if m == nil || m.Len() == 0 {
+		return errEmptySet
+	}
 
-	c.startNearestWorkers()
+	if c.index != nil {
+		return errIndexWithMatrix
+	}
 
-	c.run()
+	c.mu.Lock()
 
-	c.endNearestWorkers()
+	c.nn = nil
+	c.d = nil
+	c.dm = m
+	c.l = m.Len()
 
-	c.v = nil
-	c.p = nil
-	c.r = nil
+	c.learn()
 
 	c.mu.Unlock()
 
@@ -118,6 +200,19 @@ func (c *dbscanClusterer) Guesses() []int {
 }
 
 func (c *dbscanClusterer) Predict(p []float64) int {
+	if c.dm != nil {
+		return c.predictPrecomputed(p)
+	}
+
+	if c.nn != nil {
+		if r := c.nn.Query(p); len(r) > 0 {
+			// candidates from the grid are exact only up to eps
+			if l, m := c.predictIndexed(p, r); !c.grid() || m < c.eps {
+				return c.a[l]
+			}
+		}
+	}
+
 	var (
 		l int
 		d float64
@@ -139,6 +234,31 @@ func (c *dbscanClusterer) Online(observations chan []float64, done chan struct{}
 }
 
 // private
+func (c *dbscanClusterer) learn() {
+	c.s = numWorkers(c.l, c.workers)
+	c.o = c.s - 1
+	c.f = c.l / c.s
+
+	c.v = make([]bool, c.l)
+
+	c.a = make([]int, c.l)
+	c.b = make([]int, 0)
+
+	// neighbours are looked up in the index instead of scanning the dataset concurrently
+	if c.nn != nil {
+		c.run()
+	} else {
+		c.startNearestWorkers()
+
+		c.run()
+
+		c.endNearestWorkers()
+	}
+
+	c.v = nil
+	c.r = nil
+}
+
 func (c *dbscanClusterer) run() {
 	var (
 		n, m, l, k = 1, 0, 0, 0
@@ -195,20 +315,25 @@ func (c *dbscanClusterer) nearest(p int, l *int, r *[]int) {
 
 	*r = (*r)[:0]
 
-	c.p = c.d[p]
+	if c.nn != nil {
+		c.nearestIndexed(p, l, r)
+		return
+	}
+
+	c.p = p
 	c.r = r
 
 	c.w.Add(c.s)
 
-	for i := 0; i < c.l; i += c.f {
-		if c.l-i <= c.f {
-			b = c.l - 1
+	for i := 0; i < c.s; i++ {
+		if i == c.o {
+			b = c.l
 		} else {
-			b = i + c.f
+			b = (i + 1) * c.f
 		}
 
 		c.j <- &rangeJob{
-			a: i,
+			a: i * c.f,
 			b: b,
 		}
 	}
@@ -218,6 +343,52 @@ func (c *dbscanClusterer) nearest(p int, l *int, r *[]int) {
 	*l = len(*r)
 }
 
+// Candidates returned by the index are expected to be few, so they are scanned without the workers
+func (c *dbscanClusterer) nearestIndexed(p int, l *int, r *[]int) {
+	for _, i := range c.nn.Query(c.d[p]) {
+		if c.near(c.d[p], c.d[i]) {
+			*r = append(*r, i)
+		}
+	}
+
+	*l = len(*r)
+}
+
+func (c *dbscanClusterer) predictIndexed(p []float64, r []int) (int, float64) {
+	var (
+		l int = r[0]
+		d float64
+		m float64 = c.distance(p, c.d[r[0]])
+	)
+
+	for _, i := range r[1:] {
+		if d = c.distance(p, c.d[i]); d < m {
+			m = d
+			l = i
+		}
+	}
+
+	return l, m
+}
+
+func (c *dbscanClusterer) grid() bool {
+	_, ok := c.nn.(*gridIndex)
+	return ok
+}
+
+// observation holds distances to every data point
+func (c *dbscanClusterer) predictPrecomputed(p []float64) int {
+	var l int
+
+	for i := 1; i < len(p) && i < c.l; i++ {
+		if p[i] < p[l] {
+			l = i
+		}
+	}
+
+	return c.a[l]
+}
+
 func (c *dbscanClusterer) startNearestWorkers() {
 	c.j = make(chan *rangeJob, c.l)
 
@@ -225,7 +396,7 @@ func (c *dbscanClusterer) startNearestWorkers() {
 	c.w = &sync.WaitGroup{}
 
 	for i := 0; i < c.s; i++ {
-		go c.nearestWorker()
+		go c.nearestWorker(c.j)
 	}
 }
 
@@ -238,10 +409,10 @@ func (c *dbscanClusterer) endNearestWorkers() {
 	c.w = nil
 }
 
-func (c *dbscanClusterer) nearestWorker() {
-	for j := range c.j {
+func (c *dbscanClusterer) nearestWorker(jobs chan *rangeJob) {
+	for j := range jobs {
 		for i := j.a; i < j.b; i++ {
-			if c.distance(c.p, c.d[i]) < c.eps {
+			if c.nearIndices(c.p, i) {
 				c.m.Lock()
 				*c.r = append(*c.r, i)
 				c.m.Unlock()
@@ -252,27 +423,18 @@ func (c *dbscanClusterer) nearestWorker() {
 	}
 }
 
-func (c *dbscanClusterer) numWorkers() int {
-	var b int
-
-	if c.l < 1000 {
-		b = 1
-	} else if c.l < 10000 {
-		b = 10
-	} else if c.l < 100000 {
-		b = 100
-	} else {
-		b = 1000
+func (c *dbscanClusterer) nearIndices(p, q int) bool {
+	if c.dm != nil {
+		return c.dm.At(p, q) < c.eps
 	}
 
-	if c.workers == 0 {
-		return b
-	}
+	return c.near(c.d[p], c.d[q])
+}
 
-	if c.workers < b {
-		return c.workers
+func (c *dbscanClusterer) near(p, q []float64) bool {
+	if c.temporal == nil {
+		return c.distance(p, q) < c.eps
 	}
 
-	return b
-
+	return c.distance(p, q) < c.eps && c.temporal(p[c.column:], q[c.column:]) < c.teps
 }
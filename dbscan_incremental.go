@@ -0,0 +1,475 @@
+package clusters
+
+import (
+	"sync"
+)
+
+type incrementalDBSCANClusterer struct {
+	minpts int
+	eps    float64
+
+	distance DistanceFunc
+
+	// slices holding the cluster mapping and sizes. Access is synchronized to avoid read during computation.
+	mu   sync.RWMutex
+	a, b []int
+
+	// neighbourhoods of data points, each including the point itself
+	n [][]int
+
+	// deleted points and the indices freed by them, reused by later insertions
+	x []bool
+	f []int
+
+	// dataset
+	d [][]float64
+}
+
+// Implementation of incremental DBSCAN algorithm ("Incremental Clustering for Mining in a Data Warehousing Environment", Ester et al.)
+// which updates cluster mapping upon insertion and deletion of data points. Only clusters affected by the change are recomputed,
+// which results in merges of clusters connected by an inserted point and splits of clusters held together by a deleted one.
+func IncrementalDBSCAN(minpts int, eps float64, distance DistanceFunc) (IncrementalClusterer, error) {
+	if minpts < 1 {
+		return nil, errZeroMinpts
+	}
+
+	if eps <= 0 {
+		return nil, errZeroEpsilon
+	}
+
+	var d DistanceFunc
+	{
+		if distance != nil {
+			d = distance
+		} else {
+			d = EuclideanDistance
+		}
+	}
+
+	return &incrementalDBSCANClusterer{
+		minpts:   minpts,
+		eps:      eps,
+		distance: d,
+		a:        make([]int, 0),
+		b:        make([]int, 0),
+	}, nil
+}
+
+func (c *incrementalDBSCANClusterer) IsOnline() bool {
+	return true
+}
+
+func (c *incrementalDBSCANClusterer) WithOnline(o Online) HardClusterer {
+	return c
+}
+
+func (c *incrementalDBSCANClusterer) Learn(data [][]float64) error {
+	if len(data) == 0 {
+		return errEmptySet
+	}
+
+	c.mu.Lock()
+
+	c.a = make([]int, 0, len(data))
+	c.b = make([]int, 0)
+	c.n = make([][]int, 0, len(data))
+	c.x = make([]bool, 0, len(data))
+	c.f = make([]int, 0)
+	c.d = make([][]float64, 0, len(data))
+
+	for i := 0; i < len(data); i++ {
+		c.insert(data[i])
+	}
+
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *incrementalDBSCANClusterer) Sizes() []int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.b
+}
+
+func (c *incrementalDBSCANClusterer) Guesses() []int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.a
+}
+
+func (c *incrementalDBSCANClusterer) Predict(p []float64) int {
+	var (
+		l    = -1
+		d, m float64
+	)
+
+	for i := 0; i < len(c.d); i++ {
+		if c.x[i] {
+			continue
+		}
+
+		if d = c.distance(p, c.d[i]); l == -1 || d < m {
+			m = d
+			l = i
+		}
+	}
+
+	if l == -1 {
+		return -1
+	}
+
+	return c.a[l]
+}
+
+func (c *incrementalDBSCANClusterer) Insert(p []float64) (int, []*ICEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.insert(p)
+}
+
+func (c *incrementalDBSCANClusterer) Delete(p int) ([]*ICEvent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if p < 0 || p >= len(c.d) || c.x[p] {
+		return nil, errInvalidIndex
+	}
+
+	return c.delete(p), nil
+}
+
+func (c *incrementalDBSCANClusterer) Online(observations chan []float64, done chan struct{}) chan *HCEvent {
+	c.mu.Lock()
+
+	var r chan *HCEvent = make(chan *HCEvent)
+
+	go func() {
+		for {
+			select {
+			case o := <-observations:
+				i, _ := c.insert(o)
+
+				r <- &HCEvent{
+					Cluster:     c.a[i],
+					Observation: o,
+				}
+			case <-done:
+				c.mu.Unlock()
+
+				return
+			}
+		}
+	}()
+
+	return r
+}
+
+// private
+func (c *incrementalDBSCANClusterer) insert(p []float64) (int, []*ICEvent) {
+	var (
+		i  int
+		ns = make([]int, 0)
+		ss = make([]int, 0)
+	)
+
+	if k := len(c.f); k > 0 {
+		i = c.f[k-1]
+		c.f = c.f[:k-1]
+
+		c.d[i] = p
+		c.x[i] = false
+		c.a[i] = 0
+	} else {
+		i = len(c.d)
+
+		c.d = append(c.d, p)
+		c.x = append(c.x, false)
+		c.a = append(c.a, 0)
+		c.n = append(c.n, nil)
+	}
+
+	for j := 0; j < len(c.d); j++ {
+		if j != i && !c.x[j] && c.distance(p, c.d[j]) < c.eps {
+			ns = append(ns, j)
+		}
+	}
+
+	ns = append(ns, i)
+
+	c.n[i] = ns
+
+	// points which became core due to the insertion are the seeds of the update
+	for _, j := range ns {
+		if j == i {
+			continue
+		}
+
+		c.n[j] = append(c.n[j], i)
+
+		if len(c.n[j]) == c.minpts {
+			ss = append(ss, j)
+		}
+	}
+
+	ss = append(ss, i)
+
+	return i, c.update(ss, nil)
+}
+
+func (c *incrementalDBSCANClusterer) delete(p int) []*ICEvent {
+	var (
+		l  = c.a[p]
+		ns = c.n[p]
+		ss = make([]int, 0, len(ns))
+		r  = []*ICEvent{
+			&ICEvent{
+				Index:       p,
+				Previous:    l,
+				Cluster:     0,
+				Observation: c.d[p],
+			},
+		}
+	)
+
+	for _, j := range ns {
+		if j == p {
+			continue
+		}
+
+		for k := 0; k < len(c.n[j]); k++ {
+			if c.n[j][k] == p {
+				c.n[j] = append(c.n[j][:k], c.n[j][k+1:]...)
+				break
+			}
+		}
+
+		ss = append(ss, j)
+	}
+
+	c.x[p] = true
+	c.n[p] = nil
+	c.d[p] = nil
+	c.a[p] = 0
+	c.f = append(c.f, p)
+
+	if l > 0 {
+		c.b[l-1]--
+	}
+
+	return append(r, c.update(ss, []int{l})...)
+}
+
+/* Recompute the clusters affected by the change. Those are the clusters of seeds and their neighbours (as well as clusters
+ * given explicitly). Their core points are grouped into density connected components, each of which reuses the label
+ * of the majority of its core points unless it was already taken by another component, in which case the cluster
+ * was split and a new label is created. Components spanning several labels are the result of a merge. */
+func (c *incrementalDBSCANClusterer) update(ss []int, ls []int) []*ICEvent {
+	var (
+		t  = make(map[int]bool)
+		m  = make(map[int]bool)
+		nl = make(map[int]int)
+		u  = make(map[int]bool)
+		q  = make([]int, 0)
+		r  = make([]*ICEvent, 0)
+	)
+
+	for _, l := range ls {
+		if l > 0 {
+			t[l] = true
+		}
+	}
+
+	for _, s := range ss {
+		for _, j := range c.n[s] {
+			m[j] = true
+
+			if c.a[j] > 0 {
+				t[c.a[j]] = true
+			}
+		}
+	}
+
+	for i := 0; i < len(c.d); i++ {
+		if !c.x[i] && t[c.a[i]] {
+			m[i] = true
+		}
+	}
+
+	for i := 0; i < len(c.d); i++ {
+		if !m[i] || !c.core(i) {
+			continue
+		}
+
+		if _, ok := nl[i]; ok {
+			continue
+		}
+
+		var (
+			cs = make([]int, 0)
+			bs = make([]int, 0)
+			v  = make(map[int]bool)
+			k  = make(map[int]int)
+		)
+
+		q = append(q[:0], i)
+		v[i] = true
+
+		for len(q) > 0 {
+			j := q[0]
+			q = q[1:]
+
+			cs = append(cs, j)
+
+			if c.a[j] > 0 {
+				k[c.a[j]]++
+			}
+
+			for _, g := range c.n[j] {
+				if v[g] {
+					continue
+				}
+
+				v[g] = true
+
+				if c.core(g) {
+					q = append(q, g)
+				} else {
+					bs = append(bs, g)
+				}
+			}
+		}
+
+		var l, f int
+
+		for g, h := range k {
+			if !u[g] && (h > f || (h == f && g < l)) {
+				l = g
+				f = h
+			}
+		}
+
+		if l == 0 {
+			c.b = append(c.b, 0)
+			l = len(c.b)
+		}
+
+		u[l] = true
+
+		for _, j := range cs {
+			nl[j] = l
+		}
+
+		for _, j := range bs {
+			if _, ok := nl[j]; ok {
+				continue
+			}
+
+			// border points of clusters not affected by the change keep their membership
+			if c.a[j] > 0 && !t[c.a[j]] {
+				continue
+			}
+
+			nl[j] = l
+		}
+	}
+
+	// points left out of the recomputed clusters may still border a core point of another cluster
+	for i := range m {
+		if _, ok := nl[i]; ok {
+			continue
+		}
+
+		nl[i] = -1
+
+		for _, j := range c.n[i] {
+			if !c.core(j) {
+				continue
+			}
+
+			l, ok := nl[j]
+			if !ok {
+				l = c.a[j]
+			}
+
+			if l > 0 {
+				nl[i] = l
+				break
+			}
+		}
+	}
+
+	for i := 0; i < len(c.d); i++ {
+		l, ok := nl[i]
+		if !ok || l == c.a[i] {
+			continue
+		}
+
+		// untouched border points of other clusters do not become noise
+		if l == -1 && c.a[i] > 0 && !t[c.a[i]] {
+			continue
+		}
+
+		r = append(r, &ICEvent{
+			Index:       i,
+			Previous:    c.a[i],
+			Cluster:     l,
+			Observation: c.d[i],
+		})
+
+		if c.a[i] > 0 {
+			c.b[c.a[i]-1]--
+		}
+
+		if l > 0 {
+			c.b[l-1]++
+		}
+
+		c.a[i] = l
+	}
+
+	return append(r, c.compact()...)
+}
+
+// Remove labels left without members, moving the cluster with the last label in place of each of them
+func (c *incrementalDBSCANClusterer) compact() []*ICEvent {
+	var r = make([]*ICEvent, 0)
+
+	for l := len(c.b); l > 0; l-- {
+		if c.b[l-1] > 0 {
+			continue
+		}
+
+		k := len(c.b)
+
+		if l < k {
+			for i := 0; i < len(c.a); i++ {
+				if c.x[i] || c.a[i] != k {
+					continue
+				}
+
+				r = append(r, &ICEvent{
+					Index:       i,
+					Previous:    k,
+					Cluster:     l,
+					Observation: c.d[i],
+				})
+
+				c.a[i] = l
+			}
+
+			c.b[l-1] = c.b[k-1]
+		}
+
+		c.b = c.b[:k-1]
+	}
+
+	return r
+}
+
+func (c *incrementalDBSCANClusterer) core(p int) bool {
+	return len(c.n[p]) >= c.minpts
+}
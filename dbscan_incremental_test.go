@@ -0,0 +1,254 @@
+package clusters
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestIncrementalDBSCANMergeAndSplit(t *testing.T) {
+	var d = make([][]float64, 0)
+
+	for i := 0; i < 5; i++ {
+		d = append(d, []float64{float64(i), 0}, []float64{float64(i + 10), 0})
+	}
+
+	c, e := IncrementalDBSCAN(3, 1.5, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing incremental dbscan clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if n := countClusters(c.Sizes()); n != 2 {
+		t.Errorf("Number of clusters does not match: %d vs %d\n", n, 2)
+	}
+
+	var b int
+
+	for i := 5; i < 10; i++ {
+		j, es := c.Insert([]float64{float64(i), 0})
+		if len(es) == 0 {
+			t.Error("Insertion should result in membership changes")
+		}
+
+		if i == 7 {
+			b = j
+		}
+	}
+
+	if n := countClusters(c.Sizes()); n != 1 {
+		t.Errorf("Number of clusters after merge does not match: %d vs %d\n", n, 1)
+	}
+
+	es, e := c.Delete(b)
+	if e != nil {
+		t.Errorf("Error deleting data point: %s\n", e.Error())
+	}
+
+	if es[0].Index != b || es[0].Cluster != 0 {
+		t.Error("First event should report removal of the deleted point")
+	}
+
+	if n := countClusters(c.Sizes()); n != 2 {
+		t.Errorf("Number of clusters after split does not match: %d vs %d\n", n, 2)
+	}
+
+	if c.Guesses()[0] == c.Guesses()[1] {
+		t.Error("Points on both sides of the deleted point should belong to different clusters")
+	}
+
+	if _, e = c.Delete(b); e != errInvalidIndex {
+		t.Error("Deleting a point twice should fail")
+	}
+}
+
+func TestIncrementalDBSCANDeleteMatchesReference(t *testing.T) {
+	var (
+		r    = rand.New(rand.NewSource(1))
+		live = make(map[int][]float64)
+	)
+
+	for run := 0; run < 300; run++ {
+		c, e := IncrementalDBSCAN(4, 1, EuclideanDistance)
+		if e != nil {
+			t.Fatalf("Error initializing incremental dbscan clusterer: %s\n", e.Error())
+		}
+
+		var d = make([][]float64, 40)
+
+		for i := range d {
+			d[i] = []float64{r.Float64() * 5, r.Float64() * 5}
+		}
+
+		if e = c.Learn(d); e != nil {
+			t.Fatalf("Error learning data: %s\n", e.Error())
+		}
+
+		for k := range live {
+			delete(live, k)
+		}
+
+		for i := range d {
+			live[i] = d[i]
+		}
+
+		var peak = len(live)
+
+		for s := 0; s < 20; s++ {
+			if r.Intn(2) == 0 {
+				p := []float64{r.Float64() * 5, r.Float64() * 5}
+				i, _ := c.Insert(p)
+
+				if _, ok := live[i]; ok {
+					t.Fatalf("Insertion reused index %d of a live point\n", i)
+				}
+
+				live[i] = p
+
+				if len(live) > peak {
+					peak = len(live)
+				}
+			} else {
+				for i := range live {
+					if _, e = c.Delete(i); e != nil {
+						t.Fatalf("Error deleting data point: %s\n", e.Error())
+					}
+
+					delete(live, i)
+
+					break
+				}
+			}
+
+			if e := compareWithReference(live, c.Guesses(), c.Sizes(), 4, 1); e != "" {
+				t.Fatalf("Run %d, step %d: %s\n", run, s, e)
+			}
+		}
+
+		if n := len(c.Guesses()); n != peak {
+			t.Errorf("Indices of deleted points should be reused: %d vs %d\n", n, peak)
+		}
+	}
+}
+
+// compareWithReference checks the mapping of live points against DBSCAN computed from scratch. Border points
+// reachable from several clusters may belong to any of them.
+func compareWithReference(live map[int][]float64, guesses, sizes []int, minpts int, eps float64) string {
+	var (
+		n    = make(map[int][]int)
+		r    = make(map[int]int)
+		q    []int
+		size = make([]int, len(sizes))
+		l    int
+	)
+
+	for i, p := range live {
+		for j, o := range live {
+			if EuclideanDistance(p, o) < eps {
+				n[i] = append(n[i], j)
+			}
+		}
+	}
+
+	for i := range live {
+		if len(n[i]) < minpts || r[i] > 0 {
+			continue
+		}
+
+		l++
+		r[i] = l
+		q = append(q[:0], i)
+
+		for len(q) > 0 {
+			j := q[0]
+			q = q[1:]
+
+			for _, k := range n[j] {
+				if len(n[k]) >= minpts && r[k] == 0 {
+					r[k] = l
+					q = append(q, k)
+				}
+			}
+		}
+	}
+
+	// core points of a reference cluster share a label which no other cluster uses
+	var m = make(map[int]int)
+
+	for i := range live {
+		if len(n[i]) < minpts {
+			continue
+		}
+
+		if g, ok := m[r[i]]; ok && g != guesses[i] {
+			return "core points of one cluster have different labels"
+		}
+
+		m[r[i]] = guesses[i]
+	}
+
+	var u = make(map[int]bool)
+
+	for _, g := range m {
+		if g < 1 || u[g] {
+			return "clusters do not have distinct labels"
+		}
+
+		u[g] = true
+	}
+
+	for i := range live {
+		var g = guesses[i]
+
+		if g > 0 && g <= len(size) {
+			size[g-1]++
+		}
+
+		if len(n[i]) >= minpts {
+			continue
+		}
+
+		var b, ok bool
+
+		for _, k := range n[i] {
+			if len(n[k]) >= minpts {
+				b = true
+				ok = ok || g == m[r[k]]
+			}
+		}
+
+		if !b && g != -1 {
+			return "noise point is assigned to a cluster"
+		}
+
+		if b && !ok {
+			return "border point is not assigned to a cluster of its neighbours"
+		}
+	}
+
+	for i := range sizes {
+		if sizes[i] < 1 || sizes[i] != size[i] {
+			return "sizes do not match the mapping"
+		}
+	}
+
+	if len(sizes) != len(m) {
+		return "number of clusters does not match"
+	}
+
+	return ""
+}
+
+func countClusters(sizes []int) int {
+	var n int
+
+	for _, s := range sizes {
+		if s > 0 {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,44 @@
+package clusters
+
+// Implementation of ST-DBSCAN algorithm ("ST-DBSCAN: An algorithm for clustering spatial–temporal data", Birant et al.)
+// built on top of DBSCAN. Columns preceding the column argument hold spatial features, while the rest hold temporal ones.
+// Two points are neighbours only if their spatial distance is less than eps and temporal distance is less than teps.
+// Learn fails if the data has no temporal features. Predict takes only the spatial features into account.
+func STDBSCAN(minpts int, eps, teps float64, column, workers int, spatial, temporal DistanceFunc) (HardClusterer, error) {
+	if column < 1 {
+		return nil, errInvalidColumn
+	}
+
+	if teps <= 0 {
+		return nil, errZeroEpsilon
+	}
+
+	var s, t DistanceFunc
+	{
+		if spatial != nil {
+			s = spatial
+		} else {
+			s = EuclideanDistance
+		}
+
+		if temporal != nil {
+			t = temporal
+		} else {
+			t = EuclideanDistance
+		}
+	}
+
+	c, e := DBSCAN(minpts, eps, workers, func(a, b []float64) float64 {
+		return s(a[:column], b[:column])
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	d := c.(*dbscanClusterer)
+	d.column = column
+	d.teps = teps
+	d.temporal = t
+
+	return d, nil
+}
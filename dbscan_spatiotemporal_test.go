@@ -0,0 +1,50 @@
+package clusters
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSTDBSCANSeparatesClustersInTime(t *testing.T) {
+	const (
+		C = 2
+	)
+
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 400)
+	)
+
+	// the same location visited during two distant periods of time
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{r.NormFloat64() * 0.5, r.NormFloat64() * 0.5, float64(100*(i%C)) + r.Float64()*10}
+	}
+
+	c, e := STDBSCAN(5, 1, 5, 2, 0, EuclideanDistance, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing st-dbscan clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if len(c.Sizes()) != C {
+		t.Errorf("Number of clusters does not match: %d vs %d\n", len(c.Sizes()), C)
+	}
+}
+
+func TestSTDBSCANRejectsColumnOutOfRange(t *testing.T) {
+	var d = [][]float64{{0, 0, 0}, {1, 1, 1}}
+
+	for _, column := range []int{3, 4} {
+		c, e := STDBSCAN(2, 1, 1, column, 0, nil, nil)
+		if e != nil {
+			t.Errorf("Error initializing st-dbscan clusterer: %s\n", e.Error())
+		}
+
+		if e = c.Learn(d); e != errColumnOutOfRange {
+			t.Errorf("Learning data with column %d should fail\n", column)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package clusters
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDBSCANFindsNeighboursOfLastPoint(t *testing.T) {
+	var d = lastPointCluster()
+
+	// distance other than EuclideanDistance disables the grid, so the dataset is scanned by 10 workers
+	c, e := DBSCAN(5, 0.5, 0, ManhattanDistance)
+	if e != nil {
+		t.Errorf("Error initializing dbscan: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if len(c.Sizes()) != 1 {
+		t.Errorf("Number of clusters does not match: %d vs %d\n", len(c.Sizes()), 1)
+	}
+
+	if g := c.Guesses()[len(d)-1]; g != 1 {
+		t.Errorf("Last point should belong to the only cluster, it is %d\n", g)
+	}
+}
+
+func TestOPTICSFindsNeighboursOfLastPoint(t *testing.T) {
+	var (
+		d = lastPointCluster()
+		l int
+		r = make([]int, 0)
+	)
+
+	c, e := OPTICS(5, 0.5, 0.05, 0, ManhattanDistance)
+	if e != nil {
+		t.Errorf("Error initializing optics: %s\n", e.Error())
+	}
+
+	o := c.(*opticsClusterer)
+	o.d = d
+	o.l = len(d)
+	o.s = numWorkers(o.l, o.workers)
+	o.o = o.s - 1
+	o.f = o.l / o.s
+
+	o.startNearestWorkers()
+	o.nearest(len(d)-1, &l, &r)
+	o.endNearestWorkers()
+
+	if l != 5 {
+		t.Errorf("Number of neighbours of the last point does not match: %d vs %d\n", l, 5)
+	}
+}
+
+func TestOPTICSParameters(t *testing.T) {
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 2000)
+	)
+
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{r.NormFloat64() + float64(i%4)*10, r.NormFloat64()}
+	}
+
+	for _, eps := range []float64{1, 2, 5, 100} {
+		for _, xi := range []float64{0.05, 0.1, 0.3} {
+			c, e := OPTICS(20, eps, xi, 0, nil)
+			if e != nil {
+				t.Errorf("Error initializing optics: %s\n", e.Error())
+			}
+
+			if e = c.Learn(d); e != nil {
+				t.Errorf("Error learning data: %s\n", e.Error())
+			}
+
+			if len(c.Sizes()) != 4 {
+				t.Errorf("Number of clusters for eps %f and xi %f does not match: %d vs %d\n", eps, xi, len(c.Sizes()), 4)
+				continue
+			}
+
+			var n int
+
+			for i, g := range c.Guesses() {
+				if g == -1 {
+					n++
+				} else if g != c.Guesses()[i%4] && c.Guesses()[i%4] != -1 {
+					t.Errorf("Points of the same blob for eps %f and xi %f belong to different clusters\n", eps, xi)
+					break
+				}
+			}
+
+			if n > len(d)/100 {
+				t.Errorf("Too many noise points for eps %f and xi %f: %d\n", eps, xi, n)
+			}
+		}
+	}
+}
+
+func TestOPTICSNestedClusters(t *testing.T) {
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 2000)
+	)
+
+	// two distant groups of two close blobs each
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{r.NormFloat64()*0.5 + float64(i%2)*4 + float64(i/2%2)*50, r.NormFloat64() * 0.5}
+	}
+
+	for _, xi := range []float64{0.05, 0.1} {
+		c, e := OPTICS(20, 100, xi, 0, nil)
+		if e != nil {
+			t.Errorf("Error initializing optics: %s\n", e.Error())
+		}
+
+		if e = c.Learn(d); e != nil {
+			t.Errorf("Error learning data: %s\n", e.Error())
+		}
+
+		if len(c.Sizes()) != 4 {
+			t.Errorf("Number of clusters for xi %f does not match: %d vs %d\n", xi, len(c.Sizes()), 4)
+		}
+	}
+}
+
+// 1005 points, which is not divisible by the number of workers, of which only the last 5 are dense
+func lastPointCluster() [][]float64 {
+	var d = make([][]float64, 1005)
+
+	for i := 0; i < 1000; i++ {
+		d[i] = []float64{float64(10 * i), 0}
+	}
+
+	for i := 1000; i < len(d); i++ {
+		d[i] = []float64{-100, float64(i-1000) * 0.01}
+	}
+
+	return d
+}
@@ -0,0 +1,486 @@
+package clusters
+
+import (
+	"math"
+	"sync"
+)
+
+// micro cluster summarising a dense region of the stream with faded cluster features
+type microCluster struct {
+	// weighted linear and squared sums of absorbed points
+	cf1, cf2 []float64
+
+	// weight, time of creation and time of the last update
+	w      float64
+	t0, tu int
+}
+
+func newMicroCluster(p []float64, t int) *microCluster {
+	m := &microCluster{
+		cf1: make([]float64, len(p)),
+		cf2: make([]float64, len(p)),
+		w:   1,
+		t0:  t,
+		tu:  t,
+	}
+
+	for i := 0; i < len(p); i++ {
+		m.cf1[i] = p[i]
+		m.cf2[i] = p[i] * p[i]
+	}
+
+	return m
+}
+
+func (m *microCluster) fade(t int, lambda float64) {
+	if t == m.tu {
+		return
+	}
+
+	f := math.Pow(2, -lambda*float64(t-m.tu))
+
+	for i := 0; i < len(m.cf1); i++ {
+		m.cf1[i] *= f
+		m.cf2[i] *= f
+	}
+
+	m.w *= f
+	m.tu = t
+}
+
+func (m *microCluster) add(p []float64) {
+	for i := 0; i < len(p); i++ {
+		m.cf1[i] += p[i]
+		m.cf2[i] += p[i] * p[i]
+	}
+
+	m.w++
+}
+
+func (m *microCluster) center() []float64 {
+	c := make([]float64, len(m.cf1))
+
+	for i := 0; i < len(m.cf1); i++ {
+		c[i] = m.cf1[i] / m.w
+	}
+
+	return c
+}
+
+// radius the micro cluster would have after absorbing p
+func (m *microCluster) radiusWith(p []float64) float64 {
+	var (
+		s, l float64
+		w    = m.w + 1
+	)
+
+	for i := 0; i < len(p); i++ {
+		l = (m.cf1[i] + p[i]) / w
+		s += (m.cf2[i]+p[i]*p[i])/w - l*l
+	}
+
+	if s < 0 {
+		return 0
+	}
+
+	return math.Sqrt(s)
+}
+
+type denstreamClusterer struct {
+	eps, weight, beta, lambda float64
+
+	distance DistanceFunc
+
+	// current time of the stream (number of observations seen) and pruning period
+	t, tp int
+
+	// potential and outlier micro clusters, macro cluster numbers of potential micro clusters
+	// and centers of macro clusters. Access is synchronized to allow snapshots during online learning.
+	mm     sync.Mutex
+	pm, om []*microCluster
+	pc     []int
+	mc     [][]float64
+
+	// slices holding the cluster mapping and sizes. Access is synchronized to avoid read during computation.
+	mu   sync.RWMutex
+	a, b []int
+}
+
+// Implementation of DenStream algorithm ("Density-Based Clustering over an Evolving Data Stream with Noise", Cao et al.).
+// The stream is summarised by a bounded number of faded micro clusters of radius at most eps, which are periodically
+// pruned. Potential micro clusters need the weight of beta * mu, while the weight of mu is required for a core micro cluster
+// during the macro clustering. Lambda controls how quickly the importance of old observations decays.
+func DenStream(eps, mu, beta, lambda float64, distance DistanceFunc) (StreamClusterer, error) {
+	if eps <= 0 {
+		return nil, errZeroEpsilon
+	}
+
+	if lambda <= 0 {
+		return nil, errZeroLambda
+	}
+
+	if beta <= 0 || beta > 1 {
+		return nil, errInvalidBeta
+	}
+
+	if beta*mu <= 1 {
+		return nil, errInvalidWeight
+	}
+
+	var d DistanceFunc
+	{
+		if distance != nil {
+			d = distance
+		} else {
+			d = EuclideanDistance
+		}
+	}
+
+	return &denstreamClusterer{
+		eps:      eps,
+		weight:   mu,
+		beta:     beta,
+		lambda:   lambda,
+		tp:       int(math.Ceil(math.Log2(beta*mu/(beta*mu-1)) / lambda)),
+		distance: d,
+	}, nil
+}
+
+func (c *denstreamClusterer) IsOnline() bool {
+	return true
+}
+
+func (c *denstreamClusterer) WithOnline(o Online) HardClusterer {
+	c.mm.Lock()
+	c.reset()
+	c.mm.Unlock()
+
+	return c
+}
+
+func (c *denstreamClusterer) Learn(data [][]float64) error {
+	if len(data) == 0 {
+		return errEmptySet
+	}
+
+	c.mu.Lock()
+	c.mm.Lock()
+
+	c.reset()
+
+	for i := 0; i < len(data); i++ {
+		c.insert(data[i])
+	}
+
+	c.snapshot()
+
+	c.a = make([]int, len(data))
+	c.b = make([]int, len(c.mc))
+
+	for i := 0; i < len(data); i++ {
+		if c.a[i] = c.predict(data[i]); c.a[i] > 0 {
+			c.b[c.a[i]-1]++
+		}
+	}
+
+	c.mm.Unlock()
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *denstreamClusterer) Sizes() []int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.b
+}
+
+func (c *denstreamClusterer) Guesses() []int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.a
+}
+
+func (c *denstreamClusterer) Predict(p []float64) int {
+	c.mm.Lock()
+	defer c.mm.Unlock()
+
+	return c.predict(p)
+}
+
+func (c *denstreamClusterer) Snapshot() [][]float64 {
+	c.mm.Lock()
+	defer c.mm.Unlock()
+
+	c.snapshot()
+
+	r := make([][]float64, len(c.mc))
+	for i := 0; i < len(c.mc); i++ {
+		r[i] = append([]float64(nil), c.mc[i]...)
+	}
+
+	return r
+}
+
+func (c *denstreamClusterer) Online(observations chan []float64, done chan struct{}) chan *HCEvent {
+	c.mu.Lock()
+
+	var r chan *HCEvent = make(chan *HCEvent)
+
+	/* Each observation is absorbed by a micro cluster and reported together with the macro cluster it currently belongs to.
+	 * Since the observations are not retained, once the client quits sending new data the sizes are computed
+	 * from the weights of macro clusters and the mutex is unlocked. */
+
+	go func() {
+		for {
+			select {
+			case o := <-observations:
+				c.mm.Lock()
+				k := c.insert(o)
+				c.mm.Unlock()
+
+				r <- &HCEvent{
+					Cluster:     k,
+					Observation: o,
+				}
+			case <-done:
+				go func() {
+					c.mm.Lock()
+
+					c.snapshot()
+
+					var w = make([]float64, len(c.mc))
+
+					for i := 0; i < len(c.pm); i++ {
+						if c.pc[i] > 0 {
+							w[c.pc[i]-1] += c.pm[i].w
+						}
+					}
+
+					c.a = make([]int, 0)
+					c.b = make([]int, len(c.mc))
+
+					for i := 0; i < len(w); i++ {
+						c.b[i] = int(math.Round(w[i]))
+					}
+
+					c.mm.Unlock()
+					c.mu.Unlock()
+				}()
+
+				return
+			}
+		}
+	}()
+
+	return r
+}
+
+// private
+func (c *denstreamClusterer) reset() {
+	c.t = 0
+	c.pm = make([]*microCluster, 0)
+	c.om = make([]*microCluster, 0)
+	c.pc = make([]int, 0)
+	c.mc = make([][]float64, 0)
+}
+
+// absorbs the observation into the closest micro cluster and returns the number of macro cluster it was assigned to
+func (c *denstreamClusterer) insert(p []float64) int {
+	c.t++
+
+	var k int
+
+	if k = c.nearest(c.pm, p); k != -1 {
+		c.pm[k].fade(c.t, c.lambda)
+
+		if c.pm[k].radiusWith(p) <= c.eps {
+			c.pm[k].add(p)
+
+			return c.pc[k]
+		}
+	}
+
+	if k = c.nearest(c.om, p); k != -1 {
+		c.om[k].fade(c.t, c.lambda)
+
+		if c.om[k].radiusWith(p) <= c.eps {
+			c.om[k].add(p)
+
+			// outlier micro cluster grew into a potential one, it joins the macro clustering with the next snapshot
+			if c.om[k].w > c.beta*c.weight {
+				c.pm = append(c.pm, c.om[k])
+				c.pc = append(c.pc, -1)
+				c.om = append(c.om[:k], c.om[k+1:]...)
+			}
+		} else {
+			k = -1
+		}
+	}
+
+	if k == -1 {
+		c.om = append(c.om, newMicroCluster(p, c.t))
+	}
+
+	if c.t%c.tp == 0 {
+		c.prune()
+		c.snapshot()
+	}
+
+	return -1
+}
+
+func (c *denstreamClusterer) nearest(m []*microCluster, p []float64) int {
+	var (
+		k    = -1
+		d, n float64
+	)
+
+	for i := 0; i < len(m); i++ {
+		if d = c.distance(p, m[i].center()); k == -1 || d < n {
+			n = d
+			k = i
+		}
+	}
+
+	return k
+}
+
+func (c *denstreamClusterer) prune() {
+	var (
+		pm = c.pm[:0]
+		pc = c.pc[:0]
+		om = c.om[:0]
+		e  = math.Pow(2, -c.lambda*float64(c.tp)) - 1
+	)
+
+	for i := 0; i < len(c.pm); i++ {
+		c.pm[i].fade(c.t, c.lambda)
+
+		if c.pm[i].w >= c.beta*c.weight {
+			pm = append(pm, c.pm[i])
+			pc = append(pc, c.pc[i])
+		}
+	}
+
+	for i := 0; i < len(c.om); i++ {
+		c.om[i].fade(c.t, c.lambda)
+
+		// lower limit of weight of outlier micro cluster which could still grow into a potential one
+		if c.om[i].w >= (math.Pow(2, -c.lambda*float64(c.t-c.om[i].t0+c.tp))-1)/e {
+			om = append(om, c.om[i])
+		}
+	}
+
+	c.pm = pm
+	c.pc = pc
+	c.om = om
+}
+
+/* Macro clustering is a variant of DBSCAN run on the centers of potential micro clusters, where two of them
+ * are neighbours if their centers are closer than 2 * eps and a micro cluster is core if the total weight
+ * of its neighbourhood reaches mu */
+func (c *denstreamClusterer) snapshot() {
+	var (
+		l       = len(c.pm)
+		n       = 0
+		cs      = make([][]float64, l)
+		ns, nss []int
+		q       []int
+	)
+
+	for i := 0; i < l; i++ {
+		c.pm[i].fade(c.t, c.lambda)
+		cs[i] = c.pm[i].center()
+		c.pc[i] = 0
+	}
+
+	for i := 0; i < l; i++ {
+		if c.pc[i] != 0 {
+			continue
+		}
+
+		if !c.core(i, cs, &ns) {
+			c.pc[i] = -1
+			continue
+		}
+
+		n++
+
+		c.pc[i] = n
+
+		q = append(q[:0], ns...)
+
+		for len(q) > 0 {
+			j := q[0]
+			q = q[1:]
+
+			if c.pc[j] > 0 {
+				continue
+			}
+
+			c.pc[j] = n
+
+			if c.core(j, cs, &nss) {
+				q = append(q, nss...)
+			}
+		}
+	}
+
+	var w = make([]float64, n)
+
+	c.mc = make([][]float64, n)
+
+	for i := 0; i < n; i++ {
+		c.mc[i] = make([]float64, len(cs[0]))
+	}
+
+	for i := 0; i < l; i++ {
+		if c.pc[i] < 1 {
+			continue
+		}
+
+		for j := 0; j < len(cs[i]); j++ {
+			c.mc[c.pc[i]-1][j] += cs[i][j] * c.pm[i].w
+		}
+
+		w[c.pc[i]-1] += c.pm[i].w
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < len(c.mc[i]); j++ {
+			c.mc[i][j] /= w[i]
+		}
+	}
+}
+
+func (c *denstreamClusterer) core(p int, cs [][]float64, r *[]int) bool {
+	var w float64
+
+	*r = (*r)[:0]
+
+	for i := 0; i < len(cs); i++ {
+		if c.distance(cs[p], cs[i]) <= 2*c.eps {
+			*r = append(*r, i)
+			w += c.pm[i].w
+		}
+	}
+
+	return w >= c.weight
+}
+
+// observations which would not be absorbed by the closest potential micro cluster are outliers
+func (c *denstreamClusterer) predict(p []float64) int {
+	k := c.nearest(c.pm, p)
+	if k == -1 {
+		return -1
+	}
+
+	if c.distance(p, c.pm[k].center()) > c.eps || c.pm[k].radiusWith(p) > c.eps {
+		return -1
+	}
+
+	return c.pc[k]
+}
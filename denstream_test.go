@@ -0,0 +1,77 @@
+package clusters
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDenStreamClusterNumberMatches(t *testing.T) {
+	const (
+		C = 2
+	)
+
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 2000)
+	)
+
+	for i := 0; i < len(d); i++ {
+		o := float64(10 * (i % C))
+		d[i] = []float64{o + r.NormFloat64()*0.5, o + r.NormFloat64()*0.5}
+	}
+
+	c, e := DenStream(1, 10, 0.5, 0.01, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing denstream clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if len(c.Sizes()) != C {
+		t.Errorf("Number of clusters does not match: %d vs %d\n", len(c.Sizes()), C)
+	}
+
+	if len(c.Snapshot()) != C {
+		t.Errorf("Number of macro clusters does not match: %d vs %d\n", len(c.Snapshot()), C)
+	}
+
+	if c.Predict([]float64{1000, 1000}) != -1 {
+		t.Error("Distant observation should be classified as noise")
+	}
+}
+
+func TestDenStreamOnline(t *testing.T) {
+	var (
+		r      = rand.New(rand.NewSource(1))
+		send   = make(chan []float64)
+		finish = make(chan struct{})
+	)
+
+	c, e := DenStream(1, 10, 0.5, 0.01, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing denstream clusterer: %s\n", e.Error())
+	}
+
+	events := c.WithOnline(Online{Dimension: 2}).Online(send, finish)
+
+	go func() {
+		for range events {
+		}
+	}()
+
+	for i := 0; i < 2000; i++ {
+		send <- []float64{r.NormFloat64() * 0.5, r.NormFloat64() * 0.5}
+	}
+
+	finish <- struct{}{}
+
+	if len(c.Sizes()) != 1 {
+		t.Errorf("Number of clusters does not match: %d vs %d\n", len(c.Sizes()), 1)
+	}
+
+	if c.Predict([]float64{0, 0}) != 1 {
+		t.Error("Observation should be assigned to the only cluster")
+	}
+}
@@ -0,0 +1,202 @@
+package clusters
+
+import (
+	"math"
+)
+
+var (
+	// ManhattanDistance is the sum of absolute differences of coordinates
+	ManhattanDistance = func(a, b []float64) float64 {
+		checkDimensions(a, b)
+
+		var s float64
+
+		for i := 0; i < len(a); i++ {
+			s += math.Abs(a[i] - b[i])
+		}
+
+		return s
+	}
+
+	// ChebyshevDistance is the greatest absolute difference of coordinates
+	ChebyshevDistance = func(a, b []float64) float64 {
+		checkDimensions(a, b)
+
+		var s float64
+
+		for i := 0; i < len(a); i++ {
+			s = math.Max(s, math.Abs(a[i]-b[i]))
+		}
+
+		return s
+	}
+
+	// CanberraDistance is a weighted version of ManhattanDistance, sensitive to small changes near zero
+	CanberraDistance = func(a, b []float64) float64 {
+		checkDimensions(a, b)
+
+		var s, t float64
+
+		for i := 0; i < len(a); i++ {
+			if t = math.Abs(a[i]) + math.Abs(b[i]); t != 0 {
+				s += math.Abs(a[i]-b[i]) / t
+			}
+		}
+
+		return s
+	}
+
+	// BrayCurtisDistance is the sum of absolute differences of coordinates divided by the sum of absolute sums of coordinates
+	BrayCurtisDistance = func(a, b []float64) float64 {
+		checkDimensions(a, b)
+
+		var s, t float64
+
+		for i := 0; i < len(a); i++ {
+			s += math.Abs(a[i] - b[i])
+			t += math.Abs(a[i] + b[i])
+		}
+
+		if t == 0 {
+			return 0
+		}
+
+		return s / t
+	}
+
+	// CosineDistance is one minus the cosine of the angle between vectors. Zero vector is at distance 1 from any
+	// other vector and 0 from itself.
+	CosineDistance = func(a, b []float64) float64 {
+		checkDimensions(a, b)
+
+		return cosineDistance(a, b)
+	}
+
+	// CorrelationDistance is one minus the Pearson correlation coefficient of coordinates of vectors
+	CorrelationDistance = func(a, b []float64) float64 {
+		checkDimensions(a, b)
+
+		var ma, mb, da, db, s, na, nb float64
+
+		// running means and sums of products of deviations from them (Welford's algorithm)
+		for i := 0; i < len(a); i++ {
+			da = a[i] - ma
+			db = b[i] - mb
+			ma += da / float64(i+1)
+			mb += db / float64(i+1)
+			s += da * (b[i] - mb)
+			na += da * (a[i] - ma)
+			nb += db * (b[i] - mb)
+		}
+
+		return cosine(s, na, nb)
+	}
+
+	// HammingDistance is the proportion of coordinates which differ
+	HammingDistance = func(a, b []float64) float64 {
+		checkDimensions(a, b)
+
+		if len(a) == 0 {
+			return 0
+		}
+
+		var s float64
+
+		for i := 0; i < len(a); i++ {
+			if a[i] != b[i] {
+				s++
+			}
+		}
+
+		return s / float64(len(a))
+	}
+
+	// JaccardDistance treats non-zero coordinates as members of a set and is the proportion of coordinates which differ
+	// among those non-zero in at least one of the vectors
+	JaccardDistance = func(a, b []float64) float64 {
+		checkDimensions(a, b)
+
+		var s, t float64
+
+		for i := 0; i < len(a); i++ {
+			if a[i] == 0 && b[i] == 0 {
+				continue
+			}
+
+			t++
+
+			if a[i] != b[i] {
+				s++
+			}
+		}
+
+		if t == 0 {
+			return 0
+		}
+
+		return s / t
+	}
+)
+
+// MinkowskiDistance returns the distance of order p, a generalization of ManhattanDistance (p = 1), EuclideanDistance (p = 2)
+// and ChebyshevDistance (p = +Inf). It satisfies the triangle inequality only for p >= 1.
+func MinkowskiDistance(p float64) (DistanceFunc, error) {
+	if p <= 0 || math.IsNaN(p) {
+		return nil, errInvalidOrder
+	}
+
+	switch p {
+	case 1:
+		return ManhattanDistance, nil
+	case 2:
+		return EuclideanDistance, nil
+	}
+
+	if math.IsInf(p, 1) {
+		return ChebyshevDistance, nil
+	}
+
+	return func(a, b []float64) float64 {
+		checkDimensions(a, b)
+
+		var s float64
+
+		for i := 0; i < len(a); i++ {
+			s += math.Pow(math.Abs(a[i]-b[i]), p)
+		}
+
+		return math.Pow(s, 1/p)
+	}, nil
+}
+
+// distance functions other than EuclideanDistance panic when vectors of different dimensions are compared
+func checkDimensions(a, b []float64) {
+	if len(a) != len(b) {
+		panic(errMismatchedDimensions)
+	}
+}
+
+func cosineDistance(a, b []float64) float64 {
+	var s, na, nb float64
+
+	for i := 0; i < len(a); i++ {
+		s += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+
+	return cosine(s, na, nb)
+}
+
+// one minus the cosine of the angle given the dot product and squared norms of vectors
+func cosine(s, na, nb float64) float64 {
+	if na == 0 && nb == 0 {
+		return 0
+	}
+
+	if na == 0 || nb == 0 {
+		return 1
+	}
+
+	return math.Max(0, 1-s/math.Sqrt(na*nb))
+}
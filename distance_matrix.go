@@ -0,0 +1,74 @@
+package clusters
+
+import (
+	"math"
+)
+
+type squareMatrix struct {
+	d [][]float64
+}
+
+type condensedMatrix struct {
+	n int
+	d []float64
+}
+
+// SquareDistanceMatrix wraps a symmetric n x n matrix of distances between data points
+func SquareDistanceMatrix(matrix [][]float64) (DistanceMatrix, error) {
+	if len(matrix) == 0 {
+		return nil, errEmptySet
+	}
+
+	for i := 0; i < len(matrix); i++ {
+		if len(matrix[i]) != len(matrix) {
+			return nil, errInvalidMatrix
+		}
+	}
+
+	return &squareMatrix{
+		d: matrix,
+	}, nil
+}
+
+// CondensedDistanceMatrix wraps the upper triangle of a distance matrix (without the diagonal) stored row by row,
+// i.e. the distances d(0, 1), d(0, 2), ..., d(0, n-1), d(1, 2), ..., d(n-2, n-1)
+func CondensedDistanceMatrix(condensed []float64) (DistanceMatrix, error) {
+	if len(condensed) == 0 {
+		return nil, errEmptySet
+	}
+
+	n := int(math.Round((1 + math.Sqrt(1+8*float64(len(condensed)))) / 2))
+
+	if n*(n-1)/2 != len(condensed) {
+		return nil, errInvalidMatrix
+	}
+
+	return &condensedMatrix{
+		n: n,
+		d: condensed,
+	}, nil
+}
+
+func (m *squareMatrix) Len() int {
+	return len(m.d)
+}
+
+func (m *squareMatrix) At(i, j int) float64 {
+	return m.d[i][j]
+}
+
+func (m *condensedMatrix) Len() int {
+	return m.n
+}
+
+func (m *condensedMatrix) At(i, j int) float64 {
+	if i == j {
+		return 0
+	}
+
+	if i > j {
+		i, j = j, i
+	}
+
+	return m.d[m.n*i-i*(i+1)/2+j-i-1]
+}
@@ -0,0 +1,139 @@
+package clusters
+
+import (
+	"testing"
+)
+
+func TestCondensedDistanceMatrix(t *testing.T) {
+	var (
+		d = [][]float64{
+			[]float64{0, 0},
+			[]float64{3, 4},
+			[]float64{6, 8},
+			[]float64{0, 1},
+		}
+		s = make([]float64, 0)
+	)
+
+	for i := 0; i < len(d); i++ {
+		for j := i + 1; j < len(d); j++ {
+			s = append(s, EuclideanDistance(d[i], d[j]))
+		}
+	}
+
+	m, e := CondensedDistanceMatrix(s)
+	if e != nil {
+		t.Errorf("Error initializing distance matrix: %s\n", e.Error())
+	}
+
+	if m.Len() != len(d) {
+		t.Errorf("Distance matrix size mismatch: %d vs %d\n", m.Len(), len(d))
+	}
+
+	for i := 0; i < len(d); i++ {
+		for j := 0; j < len(d); j++ {
+			if m.At(i, j) != EuclideanDistance(d[i], d[j]) {
+				t.Errorf("Distance between %d and %d mismatch: %f vs %f\n", i, j, m.At(i, j), EuclideanDistance(d[i], d[j]))
+			}
+		}
+	}
+
+	if _, e = CondensedDistanceMatrix(s[1:]); e != errInvalidMatrix {
+		t.Error("Condensed matrix of invalid length should not be allowed")
+	}
+}
+
+func TestPrecomputedDBSCANMatchesDBSCAN(t *testing.T) {
+	var (
+		d = blobs(2, 200, 0.5)
+		m = make([][]float64, len(d))
+	)
+
+	for i := 0; i < len(d); i++ {
+		m[i] = make([]float64, len(d))
+
+		for j := 0; j < len(d); j++ {
+			m[i][j] = EuclideanDistance(d[i], d[j])
+		}
+	}
+
+	c, e := DBSCAN(5, 0.5, 0, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing dbscan clusterer: %s\n", e.Error())
+	}
+
+	p, e := PrecomputedDBSCAN(5, 0.5, 0)
+	if e != nil {
+		t.Errorf("Error initializing dbscan clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if e = p.Learn(m); e != nil {
+		t.Errorf("Error learning distance matrix: %s\n", e.Error())
+	}
+
+	for i, g := range c.Guesses() {
+		if p.Guesses()[i] != g {
+			t.Errorf("Data point %d assigned to a different cluster: %d vs %d\n", i, p.Guesses()[i], g)
+			break
+		}
+	}
+
+	if p.Predict(m[3]) != c.Guesses()[3] {
+		t.Error("Prediction from distances does not match the assigned cluster")
+	}
+}
+
+func TestPrecomputedOPTICSMatchesOPTICS(t *testing.T) {
+	var (
+		d = blobs(2, 200, 0.5)
+		m = make([][]float64, len(d))
+	)
+
+	for i := 0; i < len(d); i++ {
+		m[i] = make([]float64, len(d))
+
+		for j := 0; j < len(d); j++ {
+			m[i][j] = EuclideanDistance(d[i], d[j])
+		}
+	}
+
+	// distance other than EuclideanDistance disables the grid, so neighbours are visited in the same order
+	c, e := OPTICS(20, 1, 0.3, 0, func(a, b []float64) float64 {
+		return EuclideanDistance(a, b)
+	})
+	if e != nil {
+		t.Errorf("Error initializing optics clusterer: %s\n", e.Error())
+	}
+
+	p, e := PrecomputedOPTICS(20, 1, 0.3, 0)
+	if e != nil {
+		t.Errorf("Error initializing optics clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if e = p.Learn(m); e != nil {
+		t.Errorf("Error learning distance matrix: %s\n", e.Error())
+	}
+
+	if len(c.Sizes()) != 2 {
+		t.Errorf("Number of clusters does not match: %d vs %d\n", len(c.Sizes()), 2)
+	}
+
+	for i, g := range c.Guesses() {
+		if p.Guesses()[i] != g {
+			t.Errorf("Data point %d assigned to a different cluster: %d vs %d\n", i, p.Guesses()[i], g)
+			break
+		}
+	}
+
+	if p.Predict(m[3]) != c.Guesses()[3] {
+		t.Error("Prediction from distances does not match the assigned cluster")
+	}
+}
@@ -0,0 +1,165 @@
+package clusters
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DistanceConstructor builds a distance function from parameters given as key-value pairs
+type DistanceConstructor func(params map[string]string) (DistanceFunc, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]DistanceConstructor{
+		"euclidean":         withoutParams(EuclideanDistance),
+		"squared-euclidean": withoutParams(EuclideanDistanceSquared),
+		"manhattan":         withoutParams(ManhattanDistance),
+		"chebyshev":         withoutParams(ChebyshevDistance),
+		"canberra":          withoutParams(CanberraDistance),
+		"braycurtis":        withoutParams(BrayCurtisDistance),
+		"cosine":            withoutParams(CosineDistance),
+		"correlation":       withoutParams(CorrelationDistance),
+		"hamming":           withoutParams(HammingDistance),
+		"jaccard":           withoutParams(JaccardDistance),
+		"minkowski":         minkowskiConstructor,
+		"haversine":         geodesicConstructor(HaversineDistance),
+		"vincenty":          geodesicConstructor(VincentyDistance),
+		"dtw":               dtwConstructor,
+	}
+)
+
+// RegisterDistance makes the distance constructor available under given name in ParseDistance. Names are case insensitive
+// and cannot contain ':' character.
+func RegisterDistance(name string, constructor DistanceConstructor) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if name == "" || strings.Contains(name, ":") || constructor == nil {
+		return errInvalidDistanceSpec
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[name]; ok {
+		return errDuplicateDistance
+	}
+
+	registry[name] = constructor
+
+	return nil
+}
+
+// ParseDistance returns the distance function described by the specification consisting of the name of a registered distance,
+// optionally followed by a colon and comma separated parameters, e.g. "euclidean", "minkowski:p=3" or "haversine:order=lonlat,unit=km".
+// Built in distances are euclidean, squared-euclidean, manhattan, chebyshev, minkowski (p), canberra, braycurtis, cosine,
+// correlation, hamming, jaccard, haversine and vincenty (order: latlon or lonlat, unit: m or km) and dtw (window).
+func ParseDistance(spec string) (DistanceFunc, error) {
+	var (
+		n, a = spec, ""
+		p    = make(map[string]string)
+	)
+
+	if i := strings.Index(spec, ":"); i != -1 {
+		n, a = spec[:i], spec[i+1:]
+	}
+
+	n = strings.ToLower(strings.TrimSpace(n))
+
+	if n == "" {
+		return nil, errInvalidDistanceSpec
+	}
+
+	if strings.TrimSpace(a) != "" {
+		for _, kv := range strings.Split(a, ",") {
+			i := strings.Index(kv, "=")
+			if i == -1 {
+				return nil, errInvalidDistanceSpec
+			}
+
+			k := strings.ToLower(strings.TrimSpace(kv[:i]))
+			if k == "" {
+				return nil, errInvalidDistanceSpec
+			}
+
+			p[k] = strings.TrimSpace(kv[i+1:])
+		}
+	}
+
+	registryMu.RLock()
+	c, ok := registry[n]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, errUnknownDistance
+	}
+
+	return c(p)
+}
+
+// private
+func withoutParams(d DistanceFunc) DistanceConstructor {
+	return func(params map[string]string) (DistanceFunc, error) {
+		if len(params) > 0 {
+			return nil, errInvalidParameter
+		}
+
+		return d, nil
+	}
+}
+
+func minkowskiConstructor(params map[string]string) (DistanceFunc, error) {
+	if len(params) != 1 {
+		return nil, errInvalidParameter
+	}
+
+	p, e := strconv.ParseFloat(params["p"], 64)
+	if e != nil {
+		return nil, errInvalidParameter
+	}
+
+	return MinkowskiDistance(p)
+}
+
+func geodesicConstructor(f func(CoordinateOrder, DistanceUnit) (DistanceFunc, error)) DistanceConstructor {
+	return func(params map[string]string) (DistanceFunc, error) {
+		var (
+			o = LatLon
+			u = Metres
+		)
+
+		for k, v := range params {
+			switch {
+			case k == "order" && strings.EqualFold(v, "latlon"):
+				o = LatLon
+			case k == "order" && strings.EqualFold(v, "lonlat"):
+				o = LonLat
+			case k == "unit" && strings.EqualFold(v, "m"):
+				u = Metres
+			case k == "unit" && strings.EqualFold(v, "km"):
+				u = Kilometres
+			default:
+				return nil, errInvalidParameter
+			}
+		}
+
+		return f(o, u)
+	}
+}
+
+func dtwConstructor(params map[string]string) (DistanceFunc, error) {
+	if len(params) == 0 {
+		return DTWDistance, nil
+	}
+
+	if len(params) != 1 {
+		return nil, errInvalidParameter
+	}
+
+	w, e := strconv.Atoi(params["window"])
+	if e != nil {
+		return nil, errInvalidParameter
+	}
+
+	return DTWBandDistance(w)
+}
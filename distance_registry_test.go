@@ -0,0 +1,93 @@
+package clusters
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseDistance(t *testing.T) {
+	var (
+		a = []float64{0, 0}
+		b = []float64{3, 4}
+		s = map[string]float64{
+			"euclidean":       5,
+			"Manhattan":       7,
+			"minkowski:p=1":   7,
+			"minkowski: p=3 ": math.Pow(91, 1.0/3),
+			"chebyshev":       4,
+			"dtw:window=0":    5,
+		}
+	)
+
+	for n, v := range s {
+		d, e := ParseDistance(n)
+		if e != nil {
+			t.Errorf("Error parsing distance %s: %s\n", n, e.Error())
+			continue
+		}
+
+		if math.Abs(d(a, b)-v) > TOLERANCE {
+			t.Errorf("Distance %s mismatch: %f vs %f\n", n, d(a, b), v)
+		}
+	}
+
+	d, e := ParseDistance("haversine:order=lonlat,unit=km")
+	if e != nil {
+		t.Errorf("Error parsing distance: %s\n", e.Error())
+	}
+
+	if v := d([]float64{-0.1278, 51.5074}, []float64{2.3522, 48.8566}); math.Abs(v-343.56) > 0.1 {
+		t.Errorf("Haversine distance mismatch: %f vs %f\n", v, 343.56)
+	}
+}
+
+func TestParseDistanceErrors(t *testing.T) {
+	var s = map[string]error{
+		"":                   errInvalidDistanceSpec,
+		"unknown":            errUnknownDistance,
+		"euclidean:p=2":      errInvalidParameter,
+		"minkowski":          errInvalidParameter,
+		"minkowski:p=x":      errInvalidParameter,
+		"minkowski:p":        errInvalidDistanceSpec,
+		"haversine:unit=mi":  errInvalidParameter,
+		"minkowski:p=-1":     errInvalidOrder,
+		"dtw:window=-1":      errInvalidWindow,
+		"haversine:order=xy": errInvalidParameter,
+	}
+
+	for n, err := range s {
+		if _, e := ParseDistance(n); e != err {
+			t.Errorf("Parsing distance %q should fail with %v, got %v\n", n, err, e)
+		}
+	}
+}
+
+func TestRegisterDistance(t *testing.T) {
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "constant")
+		registryMu.Unlock()
+	}()
+
+	e := RegisterDistance("Constant", func(params map[string]string) (DistanceFunc, error) {
+		return func(a, b []float64) float64 {
+			return 1
+		}, nil
+	})
+	if e != nil {
+		t.Errorf("Error registering distance: %s\n", e.Error())
+	}
+
+	d, e := ParseDistance("constant")
+	if e != nil {
+		t.Errorf("Error parsing distance: %s\n", e.Error())
+	}
+
+	if d(nil, nil) != 1 {
+		t.Error("Registered distance was not used")
+	}
+
+	if e = RegisterDistance("euclidean", withoutParams(EuclideanDistance)); e != errDuplicateDistance {
+		t.Error("Registering a distance twice should fail")
+	}
+}
@@ -0,0 +1,149 @@
+package clusters
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDistanceMetricProperties(t *testing.T) {
+	m, e := MinkowskiDistance(3)
+	if e != nil {
+		t.Errorf("Error initializing minkowski distance: %s\n", e.Error())
+	}
+
+	var (
+		r = rand.New(rand.NewSource(1))
+		f = map[string]DistanceFunc{
+			"euclidean": EuclideanDistance,
+			"manhattan": ManhattanDistance,
+			"chebyshev": ChebyshevDistance,
+			"minkowski": m,
+			"canberra":  CanberraDistance,
+			"hamming":   HammingDistance,
+			"jaccard":   JaccardDistance,
+		}
+	)
+
+	for n, d := range f {
+		for i := 0; i < 1000; i++ {
+			var (
+				a = randomVector(r, 5)
+				b = randomVector(r, 5)
+				c = randomVector(r, 5)
+			)
+
+			if d(a, b) < 0 {
+				t.Errorf("Distance %s is negative\n", n)
+			}
+
+			if d(a, a) > TOLERANCE {
+				t.Errorf("Distance %s of a vector to itself is not 0\n", n)
+			}
+
+			if math.Abs(d(a, b)-d(b, a)) > TOLERANCE {
+				t.Errorf("Distance %s is not symmetric\n", n)
+			}
+
+			if d(a, c) > d(a, b)+d(b, c)+TOLERANCE {
+				t.Errorf("Distance %s does not satisfy triangle inequality\n", n)
+			}
+		}
+	}
+}
+
+func TestDistanceSemimetricProperties(t *testing.T) {
+	var (
+		r = rand.New(rand.NewSource(1))
+		f = map[string]DistanceFunc{
+			"braycurtis":  BrayCurtisDistance,
+			"cosine":      CosineDistance,
+			"correlation": CorrelationDistance,
+		}
+	)
+
+	for n, d := range f {
+		for i := 0; i < 1000; i++ {
+			var (
+				a = randomVector(r, 5)
+				b = randomVector(r, 5)
+			)
+
+			if d(a, b) < 0 {
+				t.Errorf("Distance %s is negative\n", n)
+			}
+
+			if d(a, a) > TOLERANCE {
+				t.Errorf("Distance %s of a vector to itself is not 0\n", n)
+			}
+
+			if math.Abs(d(a, b)-d(b, a)) > TOLERANCE {
+				t.Errorf("Distance %s is not symmetric\n", n)
+			}
+		}
+	}
+}
+
+func TestDistanceValues(t *testing.T) {
+	var (
+		a = []float64{1, 0, 2, 3}
+		b = []float64{0, 0, 4, 3}
+		m = map[string]DistanceFunc{
+			"manhattan":  ManhattanDistance,
+			"chebyshev":  ChebyshevDistance,
+			"canberra":   CanberraDistance,
+			"braycurtis": BrayCurtisDistance,
+			"hamming":    HammingDistance,
+			"jaccard":    JaccardDistance,
+		}
+		v = map[string]float64{
+			"manhattan":  3,
+			"chebyshev":  2,
+			"canberra":   1 + 2.0/6,
+			"braycurtis": 3.0 / 13,
+			"hamming":    0.5,
+			"jaccard":    2.0 / 3,
+		}
+	)
+
+	for n, d := range m {
+		if math.Abs(d(a, b)-v[n]) > TOLERANCE {
+			t.Errorf("Distance %s mismatch: %f vs %f\n", n, d(a, b), v[n])
+		}
+	}
+
+	if d := CosineDistance([]float64{1, 0}, []float64{0, 1}); math.Abs(d-1) > TOLERANCE {
+		t.Errorf("Cosine distance of orthogonal vectors should be 1, it is %f\n", d)
+	}
+
+	if d := CorrelationDistance([]float64{1, 2, 3}, []float64{3, 2, 1}); math.Abs(d-2) > TOLERANCE {
+		t.Errorf("Correlation distance of anticorrelated vectors should be 2, it is %f\n", d)
+	}
+}
+
+func TestDistanceMismatchedDimensions(t *testing.T) {
+	defer func() {
+		if r := recover(); r != errMismatchedDimensions {
+			t.Error("Comparing vectors of different dimensions should panic")
+		}
+	}()
+
+	ManhattanDistance([]float64{1, 2}, []float64{1, 2, 3})
+}
+
+func TestMinkowskiInvalidOrder(t *testing.T) {
+	if _, e := MinkowskiDistance(0); e != errInvalidOrder {
+		t.Error("Minkowski distance of order 0 should not be allowed")
+	}
+}
+
+// vectors with repeating coordinates, so that the discrete distances are meaningful
+func randomVector(r *rand.Rand, n int) []float64 {
+	v := make([]float64, n)
+
+	for i := 0; i < n; i++ {
+		v[i] = float64(r.Intn(3)) + math.Floor(r.Float64()*2)*r.Float64()
+	}
+
+	return v
+}
@@ -0,0 +1,133 @@
+package clusters
+
+import (
+	"math"
+)
+
+const (
+	// number of refinements of the barycenter performed every iteration of KMeansDBA
+	dbaIterations = 3
+)
+
+var (
+	// DTWDistance is the dynamic time warping distance between time series of possibly different lengths,
+	// i.e. the square root of the sum of squared differences along the optimal alignment
+	DTWDistance = func(a, b []float64) float64 {
+		return dtw(a, b, -1, nil)
+	}
+)
+
+// DTWBandDistance returns the dynamic time warping distance constrained to the Sakoe-Chiba band of given window,
+// so that aligned points are never further than window apart. The window is widened to the difference of lengths
+// of time series if necessary.
+func DTWBandDistance(window int) (DistanceFunc, error) {
+	if window < 0 {
+		return nil, errInvalidWindow
+	}
+
+	return func(a, b []float64) float64 {
+		return dtw(a, b, window, nil)
+	}, nil
+}
+
+// DBA refines the center of time series using DTW barycenter averaging ("A global averaging method for dynamic time warping,
+// with applications to clustering", Petitjean et al.). Each point of the center is replaced by the mean of points of time series
+// aligned with it. Passing a negative window will result in alignments being unconstrained.
+func DBA(series [][]float64, center []float64, iterations, window int) []float64 {
+	var (
+		c = append([]float64(nil), center...)
+		s = make([]float64, len(c))
+		n = make([]float64, len(c))
+		p = make([][2]int, 0)
+	)
+
+	for k := 0; k < iterations; k++ {
+		for i := 0; i < len(c); i++ {
+			s[i] = 0
+			n[i] = 0
+		}
+
+		for _, t := range series {
+			p = p[:0]
+
+			dtw(c, t, window, &p)
+
+			for _, e := range p {
+				s[e[0]] += t[e[1]]
+				n[e[0]]++
+			}
+		}
+
+		for i := 0; i < len(c); i++ {
+			if n[i] > 0 {
+				c[i] = s[i] / n[i]
+			}
+		}
+	}
+
+	return c
+}
+
+// private
+func dtw(a, b []float64, w int, p *[][2]int) float64 {
+	var (
+		n, m = len(a), len(b)
+		d    = make([][]float64, n+1)
+	)
+
+	if n == 0 || m == 0 {
+		if n == m {
+			return 0
+		}
+
+		return math.Inf(1)
+	}
+
+	if w >= 0 && w < int(math.Abs(float64(n-m))) {
+		w = int(math.Abs(float64(n - m)))
+	}
+
+	for i := 0; i <= n; i++ {
+		d[i] = make([]float64, m+1)
+
+		for j := 0; j <= m; j++ {
+			d[i][j] = math.Inf(1)
+		}
+	}
+
+	d[0][0] = 0
+
+	for i := 1; i <= n; i++ {
+		var l, h = 1, m
+
+		if w >= 0 {
+			l = int(math.Max(1, float64(i-w)))
+			h = int(math.Min(float64(m), float64(i+w)))
+		}
+
+		for j := l; j <= h; j++ {
+			t := a[i-1] - b[j-1]
+
+			d[i][j] = t*t + math.Min(d[i-1][j-1], math.Min(d[i-1][j], d[i][j-1]))
+		}
+	}
+
+	if p != nil {
+		// walk back along the optimal alignment
+		for i, j := n, m; i > 0 && j > 0; {
+			*p = append(*p, [2]int{i - 1, j - 1})
+
+			switch {
+			case d[i-1][j-1] <= d[i-1][j] && d[i-1][j-1] <= d[i][j-1]:
+				i--
+				j--
+			case d[i-1][j] <= d[i][j-1]:
+				i--
+			default:
+				j--
+			}
+		}
+	}
+
+	return math.Sqrt(d[n][m])
+}
@@ -0,0 +1,87 @@
+package clusters
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDTWDistanceOfWarpedSeries(t *testing.T) {
+	var (
+		a = []float64{1, 2, 3, 4, 3}
+		b = []float64{1, 1, 2, 3, 4, 4, 3}
+	)
+
+	if d := DTWDistance(a, b); d > TOLERANCE {
+		t.Errorf("Distance of warped series should be 0, it is %f\n", d)
+	}
+
+	d, e := DTWBandDistance(0)
+	if e != nil {
+		t.Errorf("Error initializing dtw distance: %s\n", e.Error())
+	}
+
+	if v := d([]float64{0, 1, 0}, []float64{1, 0, 0}); math.Abs(v-math.Sqrt(2)) > TOLERANCE {
+		t.Errorf("Distance within zero window should equal euclidean distance, it is %f\n", v)
+	}
+}
+
+func TestDBAAveragesShiftedSeries(t *testing.T) {
+	var (
+		s = [][]float64{
+			[]float64{0, 0, 1, 2, 1, 0, 0},
+			[]float64{0, 1, 2, 1, 0, 0, 0},
+			[]float64{0, 0, 0, 1, 2, 1, 0},
+		}
+		c = DBA(s, s[0], 5, -1)
+	)
+
+	var m float64
+
+	for i := 0; i < len(c); i++ {
+		m = math.Max(m, c[i])
+	}
+
+	// arithmetic mean would flatten the peak
+	if math.Abs(m-2) > TOLERANCE {
+		t.Errorf("Peak of the barycenter should be preserved, it is %f\n", m)
+	}
+}
+
+func TestKMeansDBASeparatesShapes(t *testing.T) {
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 40)
+	)
+
+	// sine waves stretched to varying lengths and flat series
+	for i := 0; i < len(d); i++ {
+		d[i] = make([]float64, 20+r.Intn(10))
+
+		for j := 0; j < len(d[i]); j++ {
+			if i%2 == 0 {
+				d[i][j] = math.Sin(2*math.Pi*float64(j)/float64(len(d[i]))) + r.NormFloat64()*0.05
+			} else {
+				d[i][j] = r.NormFloat64() * 0.05
+			}
+		}
+	}
+
+	c, e := KMeansDBA(100, 2, 5)
+	if e != nil {
+		t.Errorf("Error initializing kmeans clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	var g = c.Guesses()
+
+	for i := 2; i < len(g); i++ {
+		if g[i] != g[i%2] {
+			t.Errorf("Series %d assigned to a wrong cluster\n", i)
+			break
+		}
+	}
+}
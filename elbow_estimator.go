@@ -0,0 +1,94 @@
+package clusters
+
+import (
+	"math"
+	"math/rand"
+)
+
+type elbowEstimator struct {
+	iterations, max, restarts int
+
+	seed int64
+
+	distance DistanceFunc
+
+	// inertia of every number of clusters
+	w []float64
+}
+
+// Implementation of cluster number estimator using the elbow method with k-means++ as clustering algorithm. The dataset
+// is clustered into 1 to clusters clusters and the knee of the resulting inertia (within-cluster sum of squares) curve
+// is located with the Kneedle algorithm ("Finding a "Kneedle" in a Haystack: Detecting Knee Points in System Behavior",
+// Satopaa et al.). K-means++ is restarted the given number of times for every number of clusters and the lowest inertia
+// is kept, so that local minima do not bend the curve. Results depend only on the seed. The curve can be plotted using Scores.
+func ElbowEstimator(iterations, clusters, restarts int, seed int64, distance DistanceFunc) (ScoringEstimator, error) {
+	if iterations < 1 {
+		return nil, errZeroIterations
+	}
+
+	if clusters < 2 {
+		return nil, errOneCluster
+	}
+
+	if restarts < 1 {
+		return nil, errZeroRestarts
+	}
+
+	var d DistanceFunc
+	{
+		if distance != nil {
+			d = distance
+		} else {
+			d = EuclideanDistance
+		}
+	}
+
+	return &elbowEstimator{
+		iterations: iterations,
+		max:        clusters,
+		restarts:   restarts,
+		seed:       seed,
+		distance:   d,
+	}, nil
+}
+
+func (c *elbowEstimator) Estimate(data [][]float64) (int, error) {
+	if len(data) == 0 {
+		return 0, errEmptySet
+	}
+
+	if len(data) < c.max {
+		return 0, errNotEnoughData
+	}
+
+	var r = rand.New(rand.NewSource(c.seed))
+
+	c.w = make([]float64, c.max)
+
+	// the whole dataset forms a single cluster
+	c.w[0] = inertia(data, make([]int, len(data)))
+
+	for i := 2; i <= c.max; i++ {
+		c.w[i-1] = math.Inf(1)
+
+		for j := 0; j < c.restarts; j++ {
+			k := &kmeansEstimator{
+				iterations: c.iterations,
+				number:     i,
+				distance:   c.distance,
+				rand:       r,
+			}
+
+			k.learn(data)
+
+			c.w[i-1] = math.Min(c.w[i-1], inertia(data, k.a))
+		}
+	}
+
+	return knee(c.w, true) + 1, nil
+}
+
+// Inertia of every number of clusters from 1 to the maximum
+func (c *elbowEstimator) Scores() []float64 {
+	return c.w
+}
@@ -0,0 +1,40 @@
+package clusters
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestElbowEstimator(t *testing.T) {
+	const (
+		C = 4
+		M = 10
+	)
+
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 800)
+	)
+
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{r.NormFloat64() + float64(i%C)*10, r.NormFloat64() + float64(i%2)*10}
+	}
+
+	c, e := ElbowEstimator(1000, M, 10, 1, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing elbow estimator: %s\n", e.Error())
+	}
+
+	n, e := c.Estimate(d)
+	if e != nil {
+		t.Errorf("Error running test: %s\n", e.Error())
+	}
+
+	if n != C {
+		t.Errorf("Estimated number of clusters should be %d, it is %d\n", C, n)
+	}
+
+	if len(c.Scores()) != M {
+		t.Errorf("Inertia curve should have %d points, it has %d\n", M, len(c.Scores()))
+	}
+}
@@ -3,13 +3,62 @@ package clusters
 import "errors"
 
 var (
-	errEmptySet       = errors.New("Empty training set")
-	errNotTrained     = errors.New("You need to train the algorithm first")
-	errZeroIterations = errors.New("Number of iterations cannot be less than 1")
-	errOneCluster     = errors.New("Number of clusters cannot be less than 2")
-	errZeroEpsilon    = errors.New("Epsilon cannot be 0")
-	errZeroMinpts     = errors.New("MinPts cannot be 0")
-	errZeroWorkers    = errors.New("Number of workers cannot be less than 0")
-	errZeroXi         = errors.New("Xi cannot be 0")
-	errInvalidRange   = errors.New("Range is invalid")
+	errEmptySet              = errors.New("Empty training set")
+	errNotTrained            = errors.New("You need to train the algorithm first")
+	errZeroIterations        = errors.New("Number of iterations cannot be less than 1")
+	errOneCluster            = errors.New("Number of clusters cannot be less than 2")
+	errZeroEpsilon           = errors.New("Epsilon cannot be 0")
+	errZeroMinpts            = errors.New("MinPts cannot be 0")
+	errZeroWorkers           = errors.New("Number of workers cannot be less than 0")
+	errZeroXi                = errors.New("Xi cannot be 0")
+	errInvalidRange          = errors.New("Range is invalid")
+	errZeroLambda            = errors.New("Lambda cannot be 0")
+	errInvalidBeta           = errors.New("Beta must be in range (0, 1]")
+	errInvalidWeight         = errors.New("Product of beta and mu must be greater than 1")
+	errInvalidIndex          = errors.New("Index is out of range")
+	errZeroThreshold         = errors.New("Threshold cannot be 0")
+	errInvalidThresholds     = errors.New("T1 cannot be less than T2")
+	errNilCanopy             = errors.New("Canopy cannot be nil")
+	errNilIndex              = errors.New("Index cannot be nil")
+	errInvalidColumn         = errors.New("Column must be greater than 0")
+	errColumnOutOfRange      = errors.New("Column must be less than the number of dimensions")
+	errZeroRepresentatives   = errors.New("Number of representative points cannot be less than 1")
+	errInvalidAlpha          = errors.New("Alpha must be in range [0, 1]")
+	errInvalidSample         = errors.New("Sample size cannot be less than 0")
+	errInvalidPartitions     = errors.New("Number of partitions cannot be less than 0")
+	errMismatchedDimensions  = errors.New("Dimensions of vectors do not match")
+	errInvalidOrder          = errors.New("Order must be greater than 0")
+	errInvalidUnit           = errors.New("Distance unit must be greater than 0")
+	errNotEnoughData         = errors.New("Training set is too small")
+	errInvalidRegularisation = errors.New("Regularisation cannot be less than 0")
+	errSingularCovariance    = errors.New("Covariance matrix cannot be inverted")
+	errInvalidWindow         = errors.New("Window cannot be less than 0")
+	errEmptySchema           = errors.New("Schema cannot be empty")
+	errInvalidLevels         = errors.New("Number of levels cannot be less than 2")
+	errInvalidFeature        = errors.New("Feature is invalid")
+	errInvalidMatrix         = errors.New("Distance matrix is invalid")
+	errIndexWithMatrix       = errors.New("Index cannot be used with precomputed distances")
+	errInvalidDistanceSpec   = errors.New("Distance specification is invalid")
+	errUnknownDistance       = errors.New("Distance is not registered")
+	errDuplicateDistance     = errors.New("Distance is already registered")
+	errInvalidParameter      = errors.New("Parameter of distance is invalid")
+	errMemoryBudget          = errors.New("Distance matrix exceeds the memory budget")
+	errZeroTables            = errors.New("Number of tables cannot be less than 1")
+	errZeroHashes            = errors.New("Number of hashes cannot be less than 1")
+	errZeroWidth             = errors.New("Width cannot be 0")
+	errInvalidFamily         = errors.New("Family of hash functions is invalid")
+	errZeroSize              = errors.New("Size cannot be 0")
+	errGridDimensions        = errors.New("Grid index supports at most 3 dimensions")
+	errNilFactory            = errors.New("Factory cannot be nil")
+	errMismatchedLabels      = errors.New("Number of labels does not match the size of the dataset")
+	errNotEnoughClusters     = errors.New("Partition must have at least 2 clusters")
+	errInvalidCriterion      = errors.New("Information criterion is invalid")
+	errInvalidMinpts         = errors.New("MinPts must be greater than 1")
+	errZeroKDistance         = errors.New("Distance to the nearest neighbours at the knee is 0")
+	errInvalidResamples      = errors.New("Number of resamples cannot be less than 2")
+	errInvalidMeasure        = errors.New("Agreement measure is invalid")
+	errZeroReferences        = errors.New("Number of reference datasets cannot be less than 1")
+	errInvalidReference      = errors.New("Reference distribution is invalid")
+	errPrincipalComponents   = errors.New("Principal components cannot be computed")
+	errZeroRestarts          = errors.New("Number of restarts cannot be less than 1")
 )
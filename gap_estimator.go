@@ -0,0 +1,291 @@
+package clusters
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// GapReference denotes the distribution reference datasets of the gap statistic are drawn from
+type GapReference int
+
+const (
+	// UniformReference draws reference datasets uniformly over the range of every feature
+	UniformReference GapReference = iota
+	// PCAReference draws reference datasets uniformly over the box aligned with principal components of the dataset,
+	// which takes its shape into account
+	PCAReference
+)
+
+// struct denoting the number of clusters and the reference dataset (or the dataset itself if b is 0) to be clustered by workers
+type gapJob struct {
+	k, b int
+}
+
+type gapEstimator struct {
+	iterations, max, references, workers int
+
+	reference GapReference
+
+	seed int64
+
+	distance DistanceFunc
+
+	// diagnostics of every number of clusters
+	g []GapStatistic
+}
+
+// Implementation of cluster number estimator using gap statistic ("Estimating the number of clusters in a data set
+// via the gap statistic", Tibshirani et al.) with k-means++ as clustering algorithm, in which the dataset and a number
+// of reference datasets are clustered concurrently. The number of goroutines is controlled via workers argument, passing 0
+// will result in this number being the number of CPUs. Results depend only on the seed, not on the order of computation.
+// The smallest number of clusters k such that Gap(k) >= Gap(k+1) - s(k+1) is chosen, or the maximum if there is none.
+func KMeansGapEstimator(iterations, clusters, references, workers int, reference GapReference, seed int64, distance DistanceFunc) (GapEstimator, error) {
+	if iterations < 1 {
+		return nil, errZeroIterations
+	}
+
+	if clusters < 2 {
+		return nil, errOneCluster
+	}
+
+	if references < 1 {
+		return nil, errZeroReferences
+	}
+
+	if workers < 0 {
+		return nil, errZeroWorkers
+	}
+
+	if reference != UniformReference && reference != PCAReference {
+		return nil, errInvalidReference
+	}
+
+	var d DistanceFunc
+	{
+		if distance != nil {
+			d = distance
+		} else {
+			d = EuclideanDistance
+		}
+	}
+
+	return &gapEstimator{
+		iterations: iterations,
+		max:        clusters,
+		references: references,
+		workers:    workers,
+		reference:  reference,
+		seed:       seed,
+		distance:   d,
+	}, nil
+}
+
+func (c *gapEstimator) Estimate(data [][]float64) (int, error) {
+	if len(data) == 0 {
+		return 0, errEmptySet
+	}
+
+	if len(data) <= c.max {
+		return 0, errNotEnoughData
+	}
+
+	for i := 1; i < len(data); i++ {
+		if len(data[i]) != len(data[0]) {
+			return 0, errMismatchedDimensions
+		}
+	}
+
+	generate, e := c.generator(data)
+	if e != nil {
+		return 0, e
+	}
+
+	var (
+		estimated = c.max
+		wg        sync.WaitGroup
+		j         = make(chan *gapJob, c.max*(c.references+1))
+		w         = make([][]float64, c.max)
+		n         = c.workers
+	)
+
+	if n == 0 {
+		n = runtime.NumCPU()
+	}
+
+	for k := 0; k < c.max; k++ {
+		w[k] = make([]float64, c.references+1)
+
+		for b := 0; b <= c.references; b++ {
+			j <- &gapJob{
+				k: k + 1,
+				b: b,
+			}
+		}
+	}
+
+	close(j)
+
+	wg.Add(n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+
+			for g := range j {
+				// every job has its own source of randomness, so results do not depend on scheduling
+				var (
+					r = rand.New(rand.NewSource(c.seed + int64((g.k-1)*(c.references+1)+g.b)))
+					d = data
+				)
+
+				if g.b > 0 {
+					d = generate(r)
+				}
+
+				e := &kmeansEstimator{
+					iterations: c.iterations,
+					number:     g.k,
+					distance:   c.distance,
+					rand:       r,
+				}
+
+				e.learn(d)
+
+				w[g.k-1][g.b] = math.Log(inertia(d, e.a))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	c.g = make([]GapStatistic, c.max)
+
+	for k := 0; k < c.max; k++ {
+		m, s := stat.PopMeanStdDev(w[k][1:], nil)
+
+		c.g[k] = GapStatistic{
+			Clusters: k + 1,
+			Gap:      m - w[k][0],
+			Sk:       s * math.Sqrt(1+1/float64(c.references)),
+			LogWk:    w[k][0],
+		}
+	}
+
+	for k := 0; k < c.max-1; k++ {
+		if c.g[k].Gap >= c.g[k+1].Gap-c.g[k+1].Sk {
+			estimated = k + 1
+			break
+		}
+	}
+
+	return estimated, nil
+}
+
+func (c *gapEstimator) Statistics() []GapStatistic {
+	return c.g
+}
+
+// private
+
+// Returns a function drawing reference datasets of the same size as the dataset
+func (c *gapEstimator) generator(data [][]float64) (func(*rand.Rand) [][]float64, error) {
+	var (
+		n = len(data)
+		l = len(data[0])
+	)
+
+	if c.reference == UniformReference {
+		var b = bounds(data)
+
+		return func(r *rand.Rand) [][]float64 {
+			var s = make([][]float64, n)
+
+			for i := 0; i < n; i++ {
+				s[i] = make([]float64, l)
+
+				for j := 0; j < l; j++ {
+					s[i][j] = r.Float64()*(b[j][1]-b[j][0]) + b[j][0]
+				}
+			}
+
+			return s
+		}, nil
+	}
+
+	var (
+		x = mat.NewDense(n, l, nil)
+		m = make([]float64, l)
+		v mat.Dense
+		p mat.Dense
+		d mat.SVD
+	)
+
+	for i := 0; i < n; i++ {
+		x.SetRow(i, data[i])
+	}
+
+	for j := 0; j < l; j++ {
+		m[j] = stat.Mean(mat.Col(nil, j, x), nil)
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < l; j++ {
+			x.Set(i, j, x.At(i, j)-m[j])
+		}
+	}
+
+	// principal components are the right singular vectors of the centered dataset
+	if !d.Factorize(x, mat.SVDThin) {
+		return nil, errPrincipalComponents
+	}
+
+	d.VTo(&v)
+
+	p.Mul(x, &v)
+
+	var (
+		k = v.RawMatrix().Cols
+		b = make([][2]float64, k)
+	)
+
+	for j := 0; j < k; j++ {
+		b[j] = [2]float64{p.At(0, j), p.At(0, j)}
+
+		for i := 1; i < n; i++ {
+			b[j][0] = math.Min(b[j][0], p.At(i, j))
+			b[j][1] = math.Max(b[j][1], p.At(i, j))
+		}
+	}
+
+	return func(r *rand.Rand) [][]float64 {
+		var (
+			z = mat.NewDense(n, k, nil)
+			y mat.Dense
+			s = make([][]float64, n)
+		)
+
+		for i := 0; i < n; i++ {
+			for j := 0; j < k; j++ {
+				z.Set(i, j, r.Float64()*(b[j][1]-b[j][0])+b[j][0])
+			}
+		}
+
+		// back to the original coordinates
+		y.Mul(z, v.T())
+
+		for i := 0; i < n; i++ {
+			s[i] = make([]float64, l)
+
+			for j := 0; j < l; j++ {
+				s[i][j] = y.At(i, j) + m[j]
+			}
+		}
+
+		return s
+	}, nil
+}
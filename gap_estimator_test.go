@@ -0,0 +1,56 @@
+package clusters
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestKMeansGapEstimator(t *testing.T) {
+	const (
+		C = 3
+		M = 6
+	)
+
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 300)
+		m = [][]float64{{0, 0}, {20, 20}, {5, 15}}
+	)
+
+	// clusters spread diagonally, so the principal components differ from the axes
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{m[i%C][0] + r.NormFloat64(), m[i%C][1] + r.NormFloat64()}
+	}
+
+	for _, f := range []GapReference{UniformReference, PCAReference} {
+		c, e := KMeansGapEstimator(100, M, 10, 4, f, 1, nil)
+		if e != nil {
+			t.Errorf("Error initializing gap estimator: %s\n", e.Error())
+		}
+
+		n, e := c.Estimate(d)
+		if e != nil {
+			t.Errorf("Error running test: %s\n", e.Error())
+		}
+
+		if n != C {
+			t.Errorf("Estimated number of clusters should be %d, it is %d %v\n", C, n, c.Statistics())
+		}
+
+		if len(c.Statistics()) != M {
+			t.Errorf("Number of statistics should be %d, it is %d\n", M, len(c.Statistics()))
+		}
+
+		// the same seed gives the same results regardless of the number of workers
+		s, _ := KMeansGapEstimator(100, M, 10, 1, f, 1, nil)
+
+		if _, e = s.Estimate(d); e != nil {
+			t.Errorf("Error running test: %s\n", e.Error())
+		}
+
+		if !reflect.DeepEqual(c.Statistics(), s.Statistics()) {
+			t.Errorf("Statistics should be reproducible: %v vs %v\n", c.Statistics(), s.Statistics())
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package clusters
+
+import (
+	"math"
+)
+
+// CoordinateOrder denotes the order of latitude and longitude (in degrees) in the first two columns of data points
+type CoordinateOrder int
+
+// DistanceUnit denotes the unit of geodesic distance, expressed in metres
+type DistanceUnit float64
+
+const (
+	// LatLon means latitude precedes longitude
+	LatLon CoordinateOrder = iota
+	// LonLat means longitude precedes latitude
+	LonLat
+)
+
+const (
+	// Metres as the unit of distance
+	Metres DistanceUnit = 1
+	// Kilometres as the unit of distance
+	Kilometres DistanceUnit = 1000
+)
+
+const (
+	// mean radius of the Earth and parameters of WGS-84 ellipsoid, in metres
+	earthRadius   = 6371008.8
+	wgs84Major    = 6378137.0
+	wgs84Flat     = 1 / 298.257223563
+	wgs84Minor    = wgs84Major * (1 - wgs84Flat)
+	vincentyIters = 200
+	vincentyTol   = 1e-12
+)
+
+// HaversineDistance returns the great-circle distance between points on a sphere of the Earth's mean radius.
+// Used with DBSCAN or OPTICS, eps is expressed in the chosen unit, which must be positive.
+func HaversineDistance(order CoordinateOrder, unit DistanceUnit) (DistanceFunc, error) {
+	if !validUnit(unit) {
+		return nil, errInvalidUnit
+	}
+
+	return func(a, b []float64) float64 {
+		checkCoordinates(a, b)
+
+		f1, l1 := coordinates(a, order)
+		f2, l2 := coordinates(b, order)
+
+		return haversine(f1, l1, f2, l2) / float64(unit)
+	}, nil
+}
+
+// VincentyDistance returns the distance between points on the WGS-84 ellipsoid computed with Vincenty's inverse formula,
+// which is more accurate than HaversineDistance at a higher computational cost. For nearly antipodal points, where the
+// formula fails to converge, the great-circle distance is returned instead. Used with DBSCAN or OPTICS, eps is expressed
+// in the chosen unit, which must be positive.
+func VincentyDistance(order CoordinateOrder, unit DistanceUnit) (DistanceFunc, error) {
+	if !validUnit(unit) {
+		return nil, errInvalidUnit
+	}
+
+	return func(a, b []float64) float64 {
+		checkCoordinates(a, b)
+
+		f1, l1 := coordinates(a, order)
+		f2, l2 := coordinates(b, order)
+
+		return vincenty(f1, l1, f2, l2) / float64(unit)
+	}, nil
+}
+
+// private
+func validUnit(unit DistanceUnit) bool {
+	return unit > 0 && !math.IsInf(float64(unit), 1)
+}
+
+func checkCoordinates(a, b []float64) {
+	checkDimensions(a, b)
+
+	if len(a) < 2 {
+		panic(errMismatchedDimensions)
+	}
+}
+
+// latitude and longitude in radians
+func coordinates(p []float64, order CoordinateOrder) (float64, float64) {
+	if order == LonLat {
+		return p[1] * math.Pi / 180, p[0] * math.Pi / 180
+	}
+
+	return p[0] * math.Pi / 180, p[1] * math.Pi / 180
+}
+
+func haversine(f1, l1, f2, l2 float64) float64 {
+	var (
+		df = math.Sin((f2 - f1) / 2)
+		dl = math.Sin((l2 - l1) / 2)
+		h  = df*df + math.Cos(f1)*math.Cos(f2)*dl*dl
+	)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func vincenty(f1, l1, f2, l2 float64) float64 {
+	var (
+		u1 = math.Atan((1 - wgs84Flat) * math.Tan(f1))
+		u2 = math.Atan((1 - wgs84Flat) * math.Tan(f2))
+		l  = l2 - l1
+		g  = l
+
+		su1, cu1 = math.Sin(u1), math.Cos(u1)
+		su2, cu2 = math.Sin(u2), math.Cos(u2)
+
+		ss, cs, s, sa, ca2, c2m float64
+	)
+
+	for i := 0; ; i++ {
+		if i == vincentyIters {
+			return haversine(f1, l1, f2, l2)
+		}
+
+		var (
+			sg, cg = math.Sin(g), math.Cos(g)
+			x      = cu2 * sg
+			y      = cu1*su2 - su1*cu2*cg
+		)
+
+		if ss = math.Sqrt(x*x + y*y); ss == 0 {
+			return 0
+		}
+
+		cs = su1*su2 + cu1*cu2*cg
+		s = math.Atan2(ss, cs)
+		sa = cu1 * cu2 * sg / ss
+		ca2 = 1 - sa*sa
+
+		// points on the equator
+		if c2m = 0; ca2 != 0 {
+			c2m = cs - 2*su1*su2/ca2
+		}
+
+		var (
+			c = wgs84Flat / 16 * ca2 * (4 + wgs84Flat*(4-3*ca2))
+			p = g
+		)
+
+		g = l + (1-c)*wgs84Flat*sa*(s+c*ss*(c2m+c*cs*(-1+2*c2m*c2m)))
+
+		if math.Abs(g-p) < vincentyTol {
+			break
+		}
+	}
+
+	var (
+		u = ca2 * (wgs84Major*wgs84Major - wgs84Minor*wgs84Minor) / (wgs84Minor * wgs84Minor)
+		a = 1 + u/16384*(4096+u*(-768+u*(320-175*u)))
+		b = u / 1024 * (256 + u*(-128+u*(74-47*u)))
+		d = b * ss * (c2m + b/4*(cs*(-1+2*c2m*c2m)-b/6*c2m*(-3+4*ss*ss)*(-3+4*c2m*c2m)))
+	)
+
+	return wgs84Minor * a * (s - d)
+}
@@ -0,0 +1,64 @@
+package clusters
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVincentyDistance(t *testing.T) {
+	var (
+		a = []float64{-37.95103342, 144.42486789}
+		b = []float64{-37.65282114, 143.92649554}
+	)
+
+	d, e := VincentyDistance(LatLon, Metres)
+	if e != nil {
+		t.Errorf("Error initializing vincenty distance: %s\n", e.Error())
+	}
+
+	// Flinders Peak to Buninyong, the example from Vincenty's paper
+	if r := d(a, b); math.Abs(r-54972.271) > 0.01 {
+		t.Errorf("Vincenty distance mismatch: %f vs %f\n", r, 54972.271)
+	}
+}
+
+func TestHaversineDistance(t *testing.T) {
+	var (
+		a = []float64{-0.1278, 51.5074}
+		b = []float64{2.3522, 48.8566}
+	)
+
+	d, e := HaversineDistance(LonLat, Kilometres)
+	if e != nil {
+		t.Errorf("Error initializing haversine distance: %s\n", e.Error())
+	}
+
+	v, e := VincentyDistance(LonLat, Kilometres)
+	if e != nil {
+		t.Errorf("Error initializing vincenty distance: %s\n", e.Error())
+	}
+
+	if r := d(a, b); math.Abs(r-343.56) > 0.1 {
+		t.Errorf("Haversine distance mismatch: %f vs %f\n", r, 343.56)
+	}
+
+	if r := d(a, b) / v(a, b); math.Abs(r-1) > 0.005 {
+		t.Errorf("Haversine and Vincenty distances differ too much: %f vs %f\n", d(a, b), v(a, b))
+	}
+
+	if r := d(a, a); r != 0 {
+		t.Errorf("Distance of a point to itself should be 0, it is %f\n", r)
+	}
+}
+
+func TestGeodesicDistanceInvalidUnit(t *testing.T) {
+	for _, u := range []DistanceUnit{0, -1, DistanceUnit(math.Inf(1)), DistanceUnit(math.NaN())} {
+		if _, e := HaversineDistance(LatLon, u); e != errInvalidUnit {
+			t.Errorf("Haversine distance in unit %f should not be allowed\n", float64(u))
+		}
+
+		if _, e := VincentyDistance(LatLon, u); e != errInvalidUnit {
+			t.Errorf("Vincenty distance in unit %f should not be allowed\n", float64(u))
+		}
+	}
+}
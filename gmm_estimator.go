@@ -0,0 +1,293 @@
+package clusters
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// regularisation added to the diagonal of covariance matrices of mixture components
+	gmmRegularisation = 1e-6
+
+	// change of mean log-likelihood per data point below which expectation-maximization stops
+	gmmTolerance = 1e-3
+
+	// responsibility added to every component, ten times the machine epsilon, so that components without any
+	// responsibility keep a positive weight
+	gmmMinResponsibility = 10 * 2.220446049250313e-16
+)
+
+// InformationCriterion denotes the criterion used to compare gaussian mixtures with different numbers of components
+type InformationCriterion int
+
+const (
+	// BIC is the Bayesian information criterion, penalising the number of parameters with the logarithm of the size of the dataset
+	BIC InformationCriterion = iota
+	// AIC is the Akaike information criterion, penalising the number of parameters less heavily than BIC
+	AIC
+)
+
+type gmmEstimator struct {
+	iterations, max int
+
+	criterion InformationCriterion
+
+	// value of the criterion for every number of components
+	s []float64
+}
+
+// gaussian mixture fitted with expectation-maximization
+type gmm struct {
+	k, l int
+
+	// weights, means, inverse covariance matrices and logarithms of their determinants of components
+	w []float64
+	m [][]float64
+	v [][][]float64
+	g []float64
+
+	// responsibilities of components for data points
+	r [][]float64
+
+	// dataset
+	d [][]float64
+}
+
+// Implementation of cluster number estimator fitting gaussian mixtures with full covariance matrices and 1 to clusters
+// components using expectation-maximization initialized with k-means++. The number of components minimizing the information
+// criterion is chosen, which suits data believed to be normally distributed. Scores returns the criterion of every number
+// of components.
+func GMMEstimator(iterations, clusters int, criterion InformationCriterion) (ScoringEstimator, error) {
+	if iterations < 1 {
+		return nil, errZeroIterations
+	}
+
+	if clusters < 2 {
+		return nil, errOneCluster
+	}
+
+	if criterion != BIC && criterion != AIC {
+		return nil, errInvalidCriterion
+	}
+
+	return &gmmEstimator{
+		iterations: iterations,
+		max:        clusters,
+		criterion:  criterion,
+	}, nil
+}
+
+func (c *gmmEstimator) Estimate(data [][]float64) (int, error) {
+	if len(data) == 0 {
+		return 0, errEmptySet
+	}
+
+	if len(data) <= c.max {
+		return 0, errNotEnoughData
+	}
+
+	var estimated = 1
+
+	c.s = make([]float64, c.max)
+
+	for i := 1; i <= c.max; i++ {
+		g, e := newGMM(data, i)
+		if e != nil {
+			return 0, e
+		}
+
+		l, e := g.fit(c.iterations)
+		if e != nil {
+			return 0, e
+		}
+
+		p := float64(g.parameters())
+
+		if c.criterion == BIC {
+			c.s[i-1] = -2*l + p*math.Log(float64(len(data)))
+		} else {
+			c.s[i-1] = -2*l + 2*p
+		}
+
+		if c.s[i-1] < c.s[estimated-1] {
+			estimated = i
+		}
+	}
+
+	return estimated, nil
+}
+
+// Value of the criterion for every number of components from 1 to the maximum, lower is better
+func (c *gmmEstimator) Scores() []float64 {
+	return c.s
+}
+
+// private
+func newGMM(data [][]float64, k int) (*gmm, error) {
+	var g = &gmm{
+		k: k,
+		l: len(data[0]),
+		d: data,
+		r: make([][]float64, len(data)),
+	}
+
+	for i := 0; i < len(data); i++ {
+		if len(data[i]) != g.l {
+			return nil, errMismatchedDimensions
+		}
+
+		g.r[i] = make([]float64, k)
+	}
+
+	// responsibilities are initially assigned by k-means++
+	if k == 1 {
+		for i := 0; i < len(data); i++ {
+			g.r[i][0] = 1
+		}
+	} else {
+		c, e := KMeans(100, k, EuclideanDistance)
+		if e != nil {
+			return nil, e
+		}
+
+		if e = c.Learn(data); e != nil {
+			return nil, e
+		}
+
+		for i, j := range c.Guesses() {
+			g.r[i][j-1] = 1
+		}
+	}
+
+	return g, nil
+}
+
+// Expectation-maximization returning the log-likelihood of the dataset
+func (g *gmm) fit(iterations int) (float64, error) {
+	var (
+		l float64
+		p = math.Inf(-1)
+	)
+
+	for i := 0; i < iterations; i++ {
+		if e := g.maximize(); e != nil {
+			return 0, e
+		}
+
+		if l = g.expect(); (l-p)/float64(len(g.d)) < gmmTolerance {
+			break
+		}
+
+		p = l
+	}
+
+	return l, nil
+}
+
+func (g *gmm) maximize() error {
+	var (
+		n = float64(len(g.d))
+		s = make([]float64, g.k)
+	)
+
+	g.w = make([]float64, g.k)
+	g.m = make([][]float64, g.k)
+	g.v = make([][][]float64, g.k)
+	g.g = make([]float64, g.k)
+
+	for j := 0; j < g.k; j++ {
+		g.m[j] = make([]float64, g.l)
+	}
+
+	for i := 0; i < len(g.d); i++ {
+		for j := 0; j < g.k; j++ {
+			s[j] += g.r[i][j]
+			floats.AddScaled(g.m[j], g.r[i][j], g.d[i])
+		}
+	}
+
+	for j := 0; j < g.k; j++ {
+		s[j] += gmmMinResponsibility
+
+		g.w[j] = s[j] / n
+		floats.Scale(1/s[j], g.m[j])
+
+		var (
+			v = mat.NewSymDense(g.l, nil)
+			x = make([]float64, g.l)
+			c mat.Cholesky
+		)
+
+		for i := 0; i < len(g.d); i++ {
+			floats.SubTo(x, g.d[i], g.m[j])
+
+			v.SymRankOne(v, g.r[i][j]/s[j], mat.NewVecDense(g.l, x))
+		}
+
+		for a := 0; a < g.l; a++ {
+			v.SetSym(a, a, v.At(a, a)+gmmRegularisation)
+		}
+
+		if !c.Factorize(v) {
+			return errSingularCovariance
+		}
+
+		var u mat.SymDense
+		if e := c.InverseTo(&u); e != nil {
+			return errSingularCovariance
+		}
+
+		g.g[j] = c.LogDet()
+		g.v[j] = make([][]float64, g.l)
+
+		for a := 0; a < g.l; a++ {
+			g.v[j][a] = make([]float64, g.l)
+
+			for b := 0; b < g.l; b++ {
+				g.v[j][a][b] = u.At(a, b)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Updates responsibilities of components and returns the log-likelihood of the dataset
+func (g *gmm) expect() float64 {
+	var (
+		l float64
+		x = make([]float64, g.l)
+		c = float64(g.l) * math.Log(2*math.Pi)
+	)
+
+	for i := 0; i < len(g.d); i++ {
+		for j := 0; j < g.k; j++ {
+			floats.SubTo(x, g.d[i], g.m[j])
+
+			var s float64
+
+			for a := 0; a < g.l; a++ {
+				s += x[a] * floats.Dot(g.v[j][a], x)
+			}
+
+			g.r[i][j] = math.Log(g.w[j]) - (c+g.g[j]+s)/2
+		}
+
+		t := floats.LogSumExp(g.r[i])
+
+		for j := 0; j < g.k; j++ {
+			g.r[i][j] = math.Exp(g.r[i][j] - t)
+		}
+
+		l += t
+	}
+
+	return l
+}
+
+// Number of free parameters: weights, means and covariance matrices
+func (g *gmm) parameters() int {
+	return g.k - 1 + g.k*g.l + g.k*g.l*(g.l+1)/2
+}
@@ -0,0 +1,83 @@
+package clusters
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestGMMEstimator(t *testing.T) {
+	const (
+		C = 3
+		M = 6
+	)
+
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 600)
+	)
+
+	// elongated and correlated gaussian clusters
+	for i := 0; i < len(d); i++ {
+		x, y := r.NormFloat64()*3, r.NormFloat64()*0.5
+
+		d[i] = []float64{x + float64(i%C)*20, x + y + float64(i%C)*5}
+	}
+
+	for _, k := range []InformationCriterion{BIC, AIC} {
+		c, e := GMMEstimator(100, M, k)
+		if e != nil {
+			t.Errorf("Error initializing gmm estimator: %s\n", e.Error())
+		}
+
+		n, e := c.Estimate(d)
+		if e != nil {
+			t.Errorf("Error running test: %s\n", e.Error())
+		}
+
+		if n != C {
+			t.Errorf("Estimated number of components should be %d, it is %d %v\n", C, n, c.Scores())
+		}
+
+		if len(c.Scores()) != M {
+			t.Errorf("Number of scores should be %d, it is %d\n", M, len(c.Scores()))
+		}
+	}
+}
+
+func TestGMMEmptyComponent(t *testing.T) {
+	var (
+		d = [][]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {5, 5}, {6, 5}, {5, 6}, {6, 6}}
+		g = &gmm{
+			k: 3,
+			l: 2,
+			d: d,
+			r: make([][]float64, len(d)),
+		}
+	)
+
+	// the third component has no responsibility for any data point
+	for i := 0; i < len(d); i++ {
+		g.r[i] = []float64{float64(1 - i/4), float64(i / 4), 0}
+	}
+
+	if e := g.maximize(); e != nil {
+		t.Errorf("Error maximizing: %s\n", e.Error())
+	}
+
+	if g.w[2] <= 0 {
+		t.Errorf("Weight of the empty component should be positive, it is %g\n", g.w[2])
+	}
+
+	if l := g.expect(); math.IsInf(l, 0) || math.IsNaN(l) {
+		t.Errorf("Log-likelihood should be finite, it is %f\n", l)
+	}
+
+	for i := 0; i < len(d); i++ {
+		for j := 0; j < g.k; j++ {
+			if math.IsNaN(g.r[i][j]) {
+				t.Errorf("Responsibility of component %d for point %d is NaN\n", j, i)
+			}
+		}
+	}
+}
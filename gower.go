@@ -0,0 +1,108 @@
+package clusters
+
+import (
+	"math"
+)
+
+// FeatureKind denotes the type of a feature compared by GowerDistance
+type FeatureKind int
+
+const (
+	// Numeric features are compared by the absolute difference divided by their range
+	Numeric FeatureKind = iota
+	// Categorical features contribute 0 if equal and 1 otherwise
+	Categorical
+	// Ordinal features hold ranks from 0 to Levels - 1, compared by the absolute difference divided by Levels - 1
+	Ordinal
+	// Binary features are asymmetric, i.e. they are ignored if both values are 0, otherwise they contribute 0 if equal and 1 otherwise
+	Binary
+)
+
+// Feature describes a column of data compared by GowerDistance. Range is required for numeric features,
+// Levels for ordinal ones. Weight of 0 is treated as 1.
+type Feature struct {
+	Kind   FeatureKind
+	Range  float64
+	Levels int
+	Weight float64
+}
+
+// GowerDistance returns the distance for data points with mixed numeric, categorical, ordinal and binary features described by
+// the schema. It is the weighted mean of contributions of features, each in range [0, 1]. Features missing (NaN) in either
+// of the data points are skipped, while points without any comparable features are at distance 1.
+func GowerDistance(schema []Feature) (DistanceFunc, error) {
+	if len(schema) == 0 {
+		return nil, errEmptySchema
+	}
+
+	var s = make([]Feature, len(schema))
+
+	for i, f := range schema {
+		switch f.Kind {
+		case Numeric:
+			if f.Range < 0 || math.IsNaN(f.Range) {
+				return nil, errInvalidRange
+			}
+		case Ordinal:
+			if f.Levels < 2 {
+				return nil, errInvalidLevels
+			}
+		case Categorical, Binary:
+		default:
+			return nil, errInvalidFeature
+		}
+
+		if f.Weight < 0 {
+			return nil, errInvalidFeature
+		}
+
+		if f.Weight == 0 {
+			f.Weight = 1
+		}
+
+		s[i] = f
+	}
+
+	return func(a, b []float64) float64 {
+		checkDimensions(a, b)
+
+		if len(a) != len(s) {
+			panic(errMismatchedDimensions)
+		}
+
+		var d, w float64
+
+		for i, f := range s {
+			if math.IsNaN(a[i]) || math.IsNaN(b[i]) {
+				continue
+			}
+
+			switch f.Kind {
+			case Numeric:
+				if f.Range > 0 {
+					d += f.Weight * math.Min(1, math.Abs(a[i]-b[i])/f.Range)
+				}
+			case Ordinal:
+				d += f.Weight * math.Min(1, math.Abs(a[i]-b[i])/float64(f.Levels-1))
+			case Binary:
+				if a[i] == 0 && b[i] == 0 {
+					continue
+				}
+
+				fallthrough
+			case Categorical:
+				if a[i] != b[i] {
+					d += f.Weight
+				}
+			}
+
+			w += f.Weight
+		}
+
+		if w == 0 {
+			return 1
+		}
+
+		return d / w
+	}, nil
+}
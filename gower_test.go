@@ -0,0 +1,40 @@
+package clusters
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGowerDistance(t *testing.T) {
+	d, e := GowerDistance([]Feature{
+		Feature{Kind: Numeric, Range: 10},
+		Feature{Kind: Categorical},
+		Feature{Kind: Ordinal, Levels: 5},
+		Feature{Kind: Binary},
+	})
+	if e != nil {
+		t.Errorf("Error initializing gower distance: %s\n", e.Error())
+	}
+
+	var (
+		a = []float64{2, 1, 0, 0}
+		b = []float64{7, 2, 2, 0}
+		c = []float64{7, math.NaN(), 2, 1}
+	)
+
+	if v := d(a, b); math.Abs(v-(0.5+1+0.5)/3) > TOLERANCE {
+		t.Errorf("Gower distance mismatch: %f vs %f\n", v, (0.5+1+0.5)/3)
+	}
+
+	if v := d(b, c); math.Abs(v-1.0/3) > TOLERANCE {
+		t.Errorf("Gower distance with missing feature mismatch: %f vs %f\n", v, 1.0/3)
+	}
+
+	if v := d(c, c); v != 0 {
+		t.Errorf("Distance of a point to itself should be 0, it is %f\n", v)
+	}
+
+	if _, e = GowerDistance([]Feature{Feature{Kind: Ordinal, Levels: 1}}); e != errInvalidLevels {
+		t.Error("Ordinal feature with a single level should not be allowed")
+	}
+}
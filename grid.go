@@ -0,0 +1,126 @@
+package clusters
+
+import (
+	"math"
+	"reflect"
+	"sync"
+)
+
+// highest dimension of data supported by the grid index
+const gridDimensions = 3
+
+type gridCell [gridDimensions]int64
+
+type gridIndex struct {
+	size float64
+
+	// number of dimensions and points of every non-empty cell. Access is synchronized to avoid read during building.
+	mu sync.RWMutex
+	l  int
+	c  map[gridCell][]int
+}
+
+// Grid returns an index dividing the space into uniform cells of given size, suitable for low dimensional (at most 3)
+// data such as geographic coordinates. Candidates for neighbours of a point are the points in its cell and the adjacent
+// ones, so when the size is not less than eps of DBSCAN or OPTICS, no neighbour closer than eps in EuclideanDistance is missed.
+func Grid(size float64) (Index, error) {
+	if size <= 0 {
+		return nil, errZeroSize
+	}
+
+	return &gridIndex{
+		size: size,
+	}, nil
+}
+
+func (x *gridIndex) Build(data [][]float64) error {
+	if len(data) == 0 {
+		return errEmptySet
+	}
+
+	var l = len(data[0])
+
+	if l > gridDimensions {
+		return errGridDimensions
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.l = l
+	x.c = make(map[gridCell][]int)
+
+	for i := 0; i < len(data); i++ {
+		if len(data[i]) != l {
+			return errMismatchedDimensions
+		}
+
+		k := x.cell(data[i])
+
+		x.c[k] = append(x.c[k], i)
+	}
+
+	return nil
+}
+
+// Points in the cell of the observation and all the adjacent cells
+func (x *gridIndex) Query(p []float64) []int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var (
+		r = make([]int, 0)
+		k = x.cell(p)
+		o = make([]int64, x.l)
+		n gridCell
+	)
+
+	// iterate over all 3^l combinations of offsets in {-1, 0, 1}
+	for i := range o {
+		o[i] = -1
+	}
+
+	for {
+		n = k
+		for i := range o {
+			n[i] += o[i]
+		}
+
+		r = append(r, x.c[n]...)
+
+		var i int
+		for i = 0; i < len(o); i++ {
+			if o[i] < 1 {
+				o[i]++
+				break
+			}
+
+			o[i] = -1
+		}
+
+		if i == len(o) {
+			break
+		}
+	}
+
+	return r
+}
+
+func (x *gridIndex) cell(p []float64) gridCell {
+	var k gridCell
+
+	for i := 0; i < x.l && i < len(p); i++ {
+		k[i] = int64(math.Floor(p[i] / x.size))
+	}
+
+	return k
+}
+
+// Grid index is chosen automatically when the data is low dimensional and distances are euclidean
+func useGrid(data [][]float64, distance DistanceFunc) bool {
+	if len(data) == 0 || len(data[0]) > gridDimensions {
+		return false
+	}
+
+	return reflect.ValueOf(distance).Pointer() == reflect.ValueOf(EuclideanDistance).Pointer()
+}
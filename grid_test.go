@@ -0,0 +1,161 @@
+package clusters
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGridQuery(t *testing.T) {
+	const E = 0.5
+
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 1000)
+	)
+
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{r.Float64()*10 - 5, r.Float64()*10 - 5, r.Float64() * 2}
+	}
+
+	x, e := Grid(E)
+	if e != nil {
+		t.Errorf("Error initializing grid index: %s\n", e.Error())
+	}
+
+	if e = x.Build(d); e != nil {
+		t.Errorf("Error building index: %s\n", e.Error())
+	}
+
+	for i := 0; i < len(d); i++ {
+		var v = make(map[int]bool)
+		for _, j := range x.Query(d[i]) {
+			v[j] = true
+		}
+
+		for j := 0; j < len(d); j++ {
+			if EuclideanDistance(d[i], d[j]) < E && !v[j] {
+				t.Errorf("Neighbour %d of point %d is missing\n", j, i)
+			}
+		}
+	}
+}
+
+func TestGridDimensions(t *testing.T) {
+	x, _ := Grid(1)
+
+	if e := x.Build([][]float64{{1, 2, 3, 4}}); e == nil {
+		t.Error("Grid index should not accept data of more than 3 dimensions\n")
+	}
+}
+
+func TestDBSCANGrid(t *testing.T) {
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 2000)
+	)
+
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{r.NormFloat64() + float64(i%4)*5, r.NormFloat64()}
+	}
+
+	c, e := DBSCAN(5, 0.3, 1, nil)
+	if e != nil {
+		t.Errorf("Error initializing dbscan: %s\n", e.Error())
+	}
+
+	// distance other than EuclideanDistance disables the grid
+	s, e := DBSCAN(5, 0.3, 1, func(a, b []float64) float64 {
+		return EuclideanDistance(a, b)
+	})
+	if e != nil {
+		t.Errorf("Error initializing dbscan: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if e = s.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if !c.(*dbscanClusterer).grid() || s.(*dbscanClusterer).grid() {
+		t.Error("Grid index should be used only for euclidean distance\n")
+	}
+
+	var a, b = c.Guesses(), s.Guesses()
+
+	for i := 0; i < len(d); i++ {
+		if (a[i] == -1) != (b[i] == -1) {
+			t.Errorf("Noise differs for point %d: %d vs %d\n", i, a[i], b[i])
+		}
+	}
+
+	if len(c.Sizes()) != len(s.Sizes()) {
+		t.Errorf("Number of clusters differs: %d vs %d\n", len(c.Sizes()), len(s.Sizes()))
+	}
+
+	for i := 0; i < 100; i++ {
+		if a[i] != -1 && c.Predict(d[i]) != a[i] {
+			t.Errorf("Prediction differs for point %d\n", i)
+		}
+	}
+}
+
+func TestOPTICSGrid(t *testing.T) {
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 2000)
+	)
+
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{r.NormFloat64() + float64(i%4)*10, r.NormFloat64()}
+	}
+
+	c, e := OPTICS(20, 1, 0.3, 1, nil)
+	if e != nil {
+		t.Errorf("Error initializing optics: %s\n", e.Error())
+	}
+
+	// distance other than EuclideanDistance disables the grid
+	s, e := OPTICS(20, 1, 0.3, 1, func(a, b []float64) float64 {
+		return EuclideanDistance(a, b)
+	})
+	if e != nil {
+		t.Errorf("Error initializing optics: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if e = s.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if !c.(*opticsClusterer).grid() || s.(*opticsClusterer).grid() {
+		t.Error("Grid index should be used only for euclidean distance\n")
+	}
+
+	if len(c.Sizes()) != 4 {
+		t.Errorf("Number of clusters does not match: %d vs %d\n", len(c.Sizes()), 4)
+	}
+
+	if len(c.Sizes()) != len(s.Sizes()) {
+		t.Errorf("Number of clusters differs: %d vs %d\n", len(c.Sizes()), len(s.Sizes()))
+	}
+
+	var a, b = c.Guesses(), s.Guesses()
+
+	for i := 0; i < len(d); i++ {
+		if (a[i] == -1) != (b[i] == -1) {
+			t.Errorf("Noise differs for point %d: %d vs %d\n", i, a[i], b[i])
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		if a[i] != -1 && c.Predict(d[i]) != a[i] {
+			t.Errorf("Prediction differs for point %d\n", i)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package clusters
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+type kdistanceEstimator struct {
+	minpts, workers int
+
+	distance DistanceFunc
+
+	// variables used for concurrent computation of nearest neighbours
+	l, s, o, f int
+	j          chan *rangeJob
+	w          *sync.WaitGroup
+
+	// sorted distances to k-th nearest neighbour
+	k []float64
+
+	// dataset
+	d [][]float64
+}
+
+// Implementation of eps estimator for DBSCAN and OPTICS based on the k-distance graph ("A Density-Based Algorithm
+// for Discovering Clusters in Large Spatial Databases with Noise", Ester et al.). Distances of every point to its minpts-th
+// nearest neighbour, counting the point itself like DBSCAN does, are sorted and eps is taken just above the knee of the
+// resulting curve found with the Kneedle algorithm. Estimate fails if the distance at the knee is 0, e.g. for duplicate points. The number of goroutines computing distances concurrently is controlled via
+// workers argument. Passing 0 will result in this number being chosen arbitrarily.
+func KDistanceEstimator(minpts, workers int, distance DistanceFunc) (EpsEstimator, error) {
+	if minpts < 2 {
+		return nil, errInvalidMinpts
+	}
+
+	if workers < 0 {
+		return nil, errZeroWorkers
+	}
+
+	var d DistanceFunc
+	{
+		if distance != nil {
+			d = distance
+		} else {
+			d = EuclideanDistance
+		}
+	}
+
+	return &kdistanceEstimator{
+		minpts:   minpts,
+		workers:  workers,
+		distance: d,
+	}, nil
+}
+
+func (c *kdistanceEstimator) Estimate(data [][]float64) (float64, error) {
+	if len(data) == 0 {
+		return 0, errEmptySet
+	}
+
+	if len(data) < c.minpts {
+		return 0, errNotEnoughData
+	}
+
+	c.d = data
+	c.l = len(data)
+	c.s = numWorkers(c.l, c.workers)
+	c.o = c.s - 1
+	c.f = c.l / c.s
+
+	c.k = make([]float64, c.l)
+
+	c.startWorkers()
+
+	c.w.Add(c.s)
+
+	for i := 0; i < c.s; i++ {
+		var b int
+
+		if i == c.o {
+			b = c.l
+		} else {
+			b = (i + 1) * c.f
+		}
+
+		c.j <- &rangeJob{
+			a: i * c.f,
+			b: b,
+		}
+	}
+
+	c.w.Wait()
+
+	c.endWorkers()
+
+	c.d = nil
+
+	sort.Float64s(c.k)
+
+	var k = c.k[knee(c.k, false)]
+
+	if k == 0 {
+		return 0, errZeroKDistance
+	}
+
+	// neighbours are closer than eps, so points at the knee stay core
+	return math.Nextafter(k, math.Inf(1)), nil
+}
+
+// Sorted distances of points to their minpts-th nearest neighbour
+func (c *kdistanceEstimator) Curve() []float64 {
+	return c.k
+}
+
+// private
+func (c *kdistanceEstimator) startWorkers() {
+	c.j = make(chan *rangeJob, c.s)
+
+	c.w = &sync.WaitGroup{}
+
+	for i := 0; i < c.s; i++ {
+		go c.worker()
+	}
+}
+
+func (c *kdistanceEstimator) endWorkers() {
+	close(c.j)
+
+	c.j = nil
+
+	c.w = nil
+}
+
+func (c *kdistanceEstimator) worker() {
+	// distances to the nearest neighbours in ascending order, the point itself included
+	var n = make([]float64, c.minpts)
+
+	for j := range c.j {
+		for i := j.a; i < j.b; i++ {
+			n = n[:0]
+
+			for k := 0; k < c.l; k++ {
+				d := c.distance(c.d[i], c.d[k])
+
+				if len(n) == c.minpts && d >= n[c.minpts-1] {
+					continue
+				}
+
+				if len(n) < c.minpts {
+					n = append(n, d)
+				} else {
+					n[c.minpts-1] = d
+				}
+
+				for m := len(n) - 1; m > 0 && n[m] < n[m-1]; m-- {
+					n[m], n[m-1] = n[m-1], n[m]
+				}
+			}
+
+			c.k[i] = n[c.minpts-1]
+		}
+
+		c.w.Done()
+	}
+}
@@ -0,0 +1,85 @@
+package clusters
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestKDistanceEstimator(t *testing.T) {
+	const (
+		C = 3
+		M = 5
+	)
+
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 1600)
+	)
+
+	for i := 0; i < 1500; i++ {
+		d[i] = []float64{r.NormFloat64()*0.5 + float64(i%C)*10, r.NormFloat64()*0.5 + float64(i%C)*10}
+	}
+
+	// sparse noise
+	for i := 1500; i < len(d); i++ {
+		d[i] = []float64{r.Float64()*40 - 10, r.Float64()*40 - 10}
+	}
+
+	c, e := KDistanceEstimator(M, 4, nil)
+	if e != nil {
+		t.Errorf("Error initializing k-distance estimator: %s\n", e.Error())
+	}
+
+	eps, e := c.Estimate(d)
+	if e != nil {
+		t.Errorf("Error running test: %s\n", e.Error())
+	}
+
+	if len(c.Curve()) != len(d) || !sort.Float64sAreSorted(c.Curve()) {
+		t.Error("Curve should hold sorted distances of every point\n")
+	}
+
+	if k := c.Curve()[knee(c.Curve(), false)]; eps <= k {
+		t.Errorf("Estimated eps should be greater than the distance at the knee: %f vs %f\n", eps, k)
+	}
+
+	s, e := KDistanceEstimator(M, 1, nil)
+	if e != nil {
+		t.Errorf("Error initializing k-distance estimator: %s\n", e.Error())
+	}
+
+	if f, _ := s.Estimate(d); f != eps {
+		t.Errorf("Estimated eps should not depend on the number of workers: %f vs %f\n", f, eps)
+	}
+
+	b, e := DBSCAN(M, eps, 0, nil)
+	if e != nil {
+		t.Errorf("Error initializing dbscan with estimated eps %f: %s\n", eps, e.Error())
+	}
+
+	if e = b.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	if len(b.Sizes()) != C {
+		t.Errorf("Number of clusters with estimated eps %f should be %d, it is %d\n", eps, C, len(b.Sizes()))
+	}
+}
+
+func TestKDistanceEstimatorDuplicatePoints(t *testing.T) {
+	var d = make([][]float64, 100)
+
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{1, 1}
+	}
+
+	c, e := KDistanceEstimator(5, 0, nil)
+	if e != nil {
+		t.Errorf("Error initializing k-distance estimator: %s\n", e.Error())
+	}
+
+	if _, e = c.Estimate(d); e != errZeroKDistance {
+		t.Error("Estimating eps of duplicate points should fail\n")
+	}
+}
@@ -25,6 +25,12 @@ type kmeansClusterer struct {
 
 	distance DistanceFunc
 
+	// canopies determining the number and initial location of centroids, if used
+	canopy CanopyClusterer
+
+	// custom computation of centroids from members of clusters, if used
+	update func([][]float64, []float64) []float64
+
 	// slices holding the cluster mapping and sizes. Access is synchronized to avoid read during computation.
 	mu   sync.RWMutex
 	a, b []int
@@ -62,8 +68,59 @@ func KMeans(iterations, clusters int, distance DistanceFunc) (HardClusterer, err
 	}, nil
 }
 
+// Implementation of k-means algorithm seeded with canopies. The number of clusters is determined by the number of canopies
+// found in the dataset during learning, while their means become the initial centroids.
+func KMeansWithCanopy(iterations int, canopy CanopyClusterer, distance DistanceFunc) (HardClusterer, error) {
+	if iterations < 1 {
+		return nil, errZeroIterations
+	}
+
+	if canopy == nil {
+		return nil, errNilCanopy
+	}
+
+	var d DistanceFunc
+	{
+		if distance != nil {
+			d = distance
+		} else {
+			d = EuclideanDistance
+		}
+	}
+
+	return &kmeansClusterer{
+		iterations: iterations,
+		canopy:     canopy,
+		distance:   d,
+	}, nil
+}
+
+// Implementation of k-means algorithm for time series of possibly different lengths, which uses dynamic time warping distance
+// constrained by given window and DTW barycenter averaging to update centroids. Passing a negative window will result
+// in alignments being unconstrained.
+func KMeansDBA(iterations, clusters, window int) (HardClusterer, error) {
+	if iterations < 1 {
+		return nil, errZeroIterations
+	}
+
+	if clusters < 2 {
+		return nil, errOneCluster
+	}
+
+	return &kmeansClusterer{
+		iterations: iterations,
+		number:     clusters,
+		distance: func(a, b []float64) float64 {
+			return dtw(a, b, window, nil)
+		},
+		update: func(series [][]float64, center []float64) []float64 {
+			return DBA(series, center, dbaIterations, window)
+		},
+	}, nil
+}
+
 func (c *kmeansClusterer) IsOnline() bool {
-	return true
+	return c.canopy == nil && c.update == nil
 }
 
 func (c *kmeansClusterer) WithOnline(o Online) HardClusterer {
@@ -84,6 +141,18 @@ func (c *kmeansClusterer) Learn(data [][]float64) error {
 
 	c.mu.Lock()
 
+	if c.canopy != nil {
+		if e := c.canopy.Learn(data); e != nil {
+			c.mu.Unlock()
+			return e
+		}
+
+		if c.number = len(c.canopy.Centers()); c.number < 2 {
+			c.mu.Unlock()
+			return errOneCluster
+		}
+	}
+
 	c.d = data
 
 	c.a = make([]int, len(data))
@@ -94,7 +163,11 @@ func (c *kmeansClusterer) Learn(data [][]float64) error {
 	c.changes = 0
 	c.oldchanges = 0
 
-	c.initializeMeansWithData()
+	if c.canopy != nil {
+		c.initializeMeansWithCanopies()
+	} else {
+		c.initializeMeansWithData()
+	}
 
 	for i := 0; i < c.iterations && c.counter != c.threshold; i++ {
 		c.run()
@@ -256,11 +329,27 @@ func (c *kmeansClusterer) initializeMeansWithData() {
 		c.m[i] = c.d[k]
 	}
 
+	// centroids are updated in place, so they must not share memory with the dataset
 	for i := 0; i < c.number; i++ {
+		c.m[i] = append([]float64(nil), c.m[i]...)
 		c.n[i] = make([]float64, len(c.m[0]))
 	}
 }
 
+func (c *kmeansClusterer) initializeMeansWithCanopies() {
+	var m = c.canopy.Centers()
+
+	c.m = make([][]float64, c.number)
+	c.n = make([][]float64, c.number)
+
+	for i := 0; i < c.number; i++ {
+		c.m[i] = make([]float64, len(m[i]))
+		c.n[i] = make([]float64, len(m[i]))
+
+		copy(c.m[i], m[i])
+	}
+}
+
 func (c *kmeansClusterer) initializeMeans() {
 	c.m = make([][]float64, c.number)
 
@@ -304,7 +393,14 @@ func (c *kmeansClusterer) run() {
 		c.a[i] = k
 		c.b[n]++
 
-		floats.Add(c.n[n], c.d[i])
+		if c.update == nil {
+			floats.Add(c.n[n], c.d[i])
+		}
+	}
+
+	if c.update != nil {
+		c.updateMeans()
+		return
 	}
 
 	for i := 0; i < c.number; i++ {
@@ -317,6 +413,20 @@ func (c *kmeansClusterer) run() {
 	}
 }
 
+func (c *kmeansClusterer) updateMeans() {
+	var m = make([][][]float64, c.number)
+
+	for i := 0; i < len(c.d); i++ {
+		m[c.a[i]-1] = append(m[c.a[i]-1], c.d[i])
+	}
+
+	for i := 0; i < c.number; i++ {
+		if len(m[i]) > 0 {
+			c.m[i] = c.update(m[i], c.m[i])
+		}
+	}
+}
+
 func (c *kmeansClusterer) check() {
 	if c.changes == c.oldchanges {
 		c.counter++
@@ -16,6 +16,9 @@ type kmeansEstimator struct {
 
 	distance DistanceFunc
 
+	// source of randomness used instead of the global one, if set
+	rand *rand.Rand
+
 	a, b []int
 
 	// slices holding values of centroids of each clusters
@@ -128,15 +131,21 @@ func (c *kmeansEstimator) initializeMeansWithData() {
 	c.m = make([][]float64, c.number)
 	c.n = make([][]float64, c.number)
 
-	rand.Seed(time.Now().UTC().Unix())
-
 	var (
 		k          int
 		s, t, l, f float64
 		d          []float64 = make([]float64, len(c.d))
+		intn                 = rand.Intn
+		float                = rand.Float64
 	)
 
-	c.m[0] = c.d[rand.Intn(len(c.d)-1)]
+	if c.rand != nil {
+		intn, float = c.rand.Intn, c.rand.Float64
+	} else {
+		rand.Seed(time.Now().UTC().Unix())
+	}
+
+	c.m[0] = c.d[intn(len(c.d)-1)]
 
 	for i := 1; i < c.number; i++ {
 		s = 0
@@ -154,7 +163,7 @@ func (c *kmeansEstimator) initializeMeansWithData() {
 			s += d[j]
 		}
 
-		t = rand.Float64() * s
+		t = float() * s
 		k = 0
 		for s = d[0]; s < t; s += d[k] {
 			k++
@@ -163,7 +172,9 @@ func (c *kmeansEstimator) initializeMeansWithData() {
 		c.m[i] = c.d[k]
 	}
 
+	// centroids are updated in place, so they must not share memory with the dataset
 	for i := 0; i < c.number; i++ {
+		c.m[i] = append([]float64(nil), c.m[i]...)
 		c.n[i] = make([]float64, len(c.m[0]))
 	}
 }
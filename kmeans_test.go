@@ -32,3 +32,22 @@ func TestKmeansClusterNumerMatches(t *testing.T) {
 		t.Errorf("Number of clusters does not match: %d vs %d\n", len(c.Sizes()), C)
 	}
 }
+
+func TestKmeansDoesNotModifyData(t *testing.T) {
+	var d = [][]float64{{0, 0}, {1, 1}, {10, 10}, {11, 11}, {20, 0}, {21, 1}}
+
+	c, e := KMeans(1000, 3, EuclideanDistance)
+	if e != nil {
+		t.Errorf("Error initializing kmeans clusterer: %s\n", e.Error())
+	}
+
+	if e = c.Learn(d); e != nil {
+		t.Errorf("Error learning data: %s\n", e.Error())
+	}
+
+	for i, p := range [][]float64{{0, 0}, {1, 1}, {10, 10}, {11, 11}, {20, 0}, {21, 1}} {
+		if d[i][0] != p[0] || d[i][1] != p[1] {
+			t.Errorf("Data point %d was modified: %v\n", i, d[i])
+		}
+	}
+}
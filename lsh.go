@@ -0,0 +1,156 @@
+package clusters
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// LSHFamily denotes the family of locality-sensitive hash functions used by the LSH index
+type LSHFamily int
+
+const (
+	// CosineLSH hashes points by the sides of random hyperplanes they lie on, approximating CosineDistance
+	CosineLSH LSHFamily = iota
+	// EuclideanLSH hashes points by quantized random projections (p-stable distributions), approximating EuclideanDistance
+	EuclideanLSH
+)
+
+type lshIndex struct {
+	family         LSHFamily
+	tables, hashes int
+	width          float64
+
+	// random projections and offsets of hash functions of every table, and buckets of tables.
+	// Access is synchronized to avoid read during building.
+	mu sync.RWMutex
+	p  [][][]float64
+	o  [][]float64
+	b  []map[uint64][]int
+}
+
+// LSH returns an approximate nearest neighbour index based on locality-sensitive hashing ("Similarity Search in High Dimensions
+// via Hashing", Gionis et al.; "Locality-Sensitive Hashing Scheme Based on p-Stable Distributions", Datar et al.), suitable
+// for high dimensional data. A point is hashed into a bucket of every table using a number of hash functions and candidates
+// for its neighbours are the points sharing any of the buckets. More tables improve recall at the cost of speed, while more hashes
+// per table make buckets smaller. For EuclideanLSH the width of quantization should be a few times larger than eps of DBSCAN
+// or OPTICS, while it is ignored for CosineLSH.
+func LSH(family LSHFamily, tables, hashes int, width float64) (Index, error) {
+	if tables < 1 {
+		return nil, errZeroTables
+	}
+
+	if hashes < 1 {
+		return nil, errZeroHashes
+	}
+
+	if family == EuclideanLSH && width <= 0 {
+		return nil, errZeroWidth
+	}
+
+	if family != CosineLSH && family != EuclideanLSH {
+		return nil, errInvalidFamily
+	}
+
+	return &lshIndex{
+		family: family,
+		tables: tables,
+		hashes: hashes,
+		width:  width,
+	}, nil
+}
+
+func (x *lshIndex) Build(data [][]float64) error {
+	if len(data) == 0 {
+		return errEmptySet
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	var l = len(data[0])
+
+	x.p = make([][][]float64, x.tables)
+	x.o = make([][]float64, x.tables)
+	x.b = make([]map[uint64][]int, x.tables)
+
+	for i := 0; i < x.tables; i++ {
+		x.p[i] = make([][]float64, x.hashes)
+		x.o[i] = make([]float64, x.hashes)
+		x.b[i] = make(map[uint64][]int)
+
+		for j := 0; j < x.hashes; j++ {
+			x.p[i][j] = make([]float64, l)
+
+			for k := 0; k < l; k++ {
+				x.p[i][j][k] = rand.NormFloat64()
+			}
+
+			x.o[i][j] = rand.Float64() * x.width
+		}
+	}
+
+	for i := 0; i < len(data); i++ {
+		if len(data[i]) != l {
+			return errMismatchedDimensions
+		}
+
+		for j := 0; j < x.tables; j++ {
+			h := x.hash(j, data[i])
+
+			x.b[j][h] = append(x.b[j][h], i)
+		}
+	}
+
+	return nil
+}
+
+func (x *lshIndex) Query(p []float64) []int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var (
+		r = make([]int, 0)
+		v = make(map[int]bool)
+	)
+
+	for i := 0; i < len(x.b); i++ {
+		for _, j := range x.b[i][x.hash(i, p)] {
+			if !v[j] {
+				v[j] = true
+				r = append(r, j)
+			}
+		}
+	}
+
+	return r
+}
+
+// private
+func (x *lshIndex) hash(t int, p []float64) uint64 {
+	var h uint64 = 14695981039346656037
+
+	for i := 0; i < x.hashes; i++ {
+		var s float64
+
+		for j := 0; j < len(p); j++ {
+			s += x.p[t][i][j] * p[j]
+		}
+
+		if x.family == CosineLSH {
+			if s >= 0 {
+				s = 1
+			} else {
+				s = 0
+			}
+		} else {
+			s = math.Floor((s + x.o[t][i]) / x.width)
+		}
+
+		// FNV-1a style combination of hash values
+		h ^= uint64(int64(s))
+		h *= 1099511628211
+	}
+
+	return h
+}
@@ -0,0 +1,137 @@
+package clusters
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestLSHRecall(t *testing.T) {
+	const (
+		E = 13
+		R = 0.9
+	)
+
+	var (
+		r = rand.New(rand.NewSource(1))
+		c = make([][]float64, 20)
+		d = make([][]float64, 2000)
+	)
+
+	// high dimensional clusters
+	for i := 0; i < len(c); i++ {
+		c[i] = make([]float64, 64)
+
+		for j := 0; j < len(c[i]); j++ {
+			c[i][j] = r.NormFloat64() * 5
+		}
+	}
+
+	for i := 0; i < len(d); i++ {
+		d[i] = make([]float64, 64)
+
+		for j := 0; j < len(d[i]); j++ {
+			d[i][j] = c[i%len(c)][j] + r.NormFloat64()
+		}
+	}
+
+	x, e := LSH(EuclideanLSH, 10, 4, 4*E)
+	if e != nil {
+		t.Errorf("Error initializing lsh index: %s\n", e.Error())
+	}
+
+	if e = x.Build(d); e != nil {
+		t.Errorf("Error building index: %s\n", e.Error())
+	}
+
+	var f, n, s float64
+
+	for i := 0; i < 100; i++ {
+		var q = x.Query(d[i])
+
+		var v = make(map[int]bool)
+		for _, j := range q {
+			v[j] = true
+		}
+
+		for j := 0; j < len(d); j++ {
+			if EuclideanDistance(d[i], d[j]) < E {
+				n++
+
+				if v[j] {
+					f++
+				}
+			}
+		}
+
+		s += float64(len(q))
+	}
+
+	if f/n < R {
+		t.Errorf("Recall of lsh index is too low: %f vs %f\n", f/n, R)
+	}
+
+	if s/100 > float64(len(d))/2 {
+		t.Errorf("Index returns too many candidates: %f\n", s/100)
+	}
+}
+
+func TestLSHCosineRecall(t *testing.T) {
+	const (
+		E = 0.2
+		R = 0.9
+	)
+
+	var (
+		r = rand.New(rand.NewSource(1))
+		c = make([][]float64, 20)
+		d = make([][]float64, 2000)
+	)
+
+	for i := 0; i < len(c); i++ {
+		c[i] = make([]float64, 64)
+
+		for j := 0; j < len(c[i]); j++ {
+			c[i][j] = r.NormFloat64()
+		}
+	}
+
+	for i := 0; i < len(d); i++ {
+		d[i] = make([]float64, 64)
+
+		for j := 0; j < len(d[i]); j++ {
+			d[i][j] = c[i%len(c)][j] + r.NormFloat64()*0.2
+		}
+	}
+
+	x, e := LSH(CosineLSH, 10, 8, 0)
+	if e != nil {
+		t.Errorf("Error initializing lsh index: %s\n", e.Error())
+	}
+
+	if e = x.Build(d); e != nil {
+		t.Errorf("Error building index: %s\n", e.Error())
+	}
+
+	var f, n float64
+
+	for i := 0; i < 100; i++ {
+		var v = make(map[int]bool)
+		for _, j := range x.Query(d[i]) {
+			v[j] = true
+		}
+
+		for j := 0; j < len(d); j++ {
+			if CosineDistance(d[i], d[j]) < E {
+				n++
+
+				if v[j] {
+					f++
+				}
+			}
+		}
+	}
+
+	if f/n < R {
+		t.Errorf("Recall of lsh index is too low: %f vs %f\n", f/n, R)
+	}
+}
@@ -0,0 +1,113 @@
+package clusters
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+const (
+	// number of attempts at inverting the covariance matrix with growing regularisation
+	regularisationAttempts = 10
+)
+
+// MahalanobisDistance returns the distance accounting for correlations between features, computed using the inverse
+// of covariance matrix of the dataset. If the covariance matrix is singular, regularisation is added to its diagonal
+// and increased tenfold until the matrix can be inverted. Passing 0 as regularisation will result in its initial value
+// being chosen relative to the variances of features.
+func MahalanobisDistance(data [][]float64, regularisation float64) (DistanceFunc, error) {
+	if len(data) == 0 {
+		return nil, errEmptySet
+	}
+
+	if len(data) < 2 {
+		return nil, errNotEnoughData
+	}
+
+	if regularisation < 0 {
+		return nil, errInvalidRegularisation
+	}
+
+	var (
+		l = len(data[0])
+		x = mat.NewDense(len(data), l, nil)
+		s = mat.NewSymDense(l, nil)
+		r = mat.NewSymDense(l, nil)
+		v = mat.NewSymDense(l, nil)
+		c mat.Cholesky
+	)
+
+	for i := 0; i < len(data); i++ {
+		if len(data[i]) != l {
+			return nil, errMismatchedDimensions
+		}
+
+		x.SetRow(i, data[i])
+	}
+
+	stat.CovarianceMatrix(s, x, nil)
+
+	if regularisation == 0 {
+		regularisation = 1e-9 * math.Max(mat.Trace(s)/float64(l), 1)
+	}
+
+	r.CopySym(s)
+
+	for i := 0; ; i++ {
+		if c.Factorize(r) && c.InverseTo(v) == nil {
+			break
+		}
+
+		if i == regularisationAttempts {
+			return nil, errSingularCovariance
+		}
+
+		r.CopySym(s)
+
+		for j := 0; j < l; j++ {
+			r.SetSym(j, j, s.At(j, j)+regularisation)
+		}
+
+		regularisation *= 10
+	}
+
+	var m = make([][]float64, l)
+
+	for i := 0; i < l; i++ {
+		m[i] = make([]float64, l)
+
+		for j := 0; j < l; j++ {
+			m[i][j] = v.At(i, j)
+		}
+	}
+
+	return func(a, b []float64) float64 {
+		checkDimensions(a, b)
+
+		if len(a) != l {
+			panic(errMismatchedDimensions)
+		}
+
+		var (
+			s, t float64
+			d    = make([]float64, l)
+		)
+
+		for i := 0; i < l; i++ {
+			d[i] = a[i] - b[i]
+		}
+
+		for i := 0; i < l; i++ {
+			t = 0
+
+			for j := 0; j < l; j++ {
+				t += m[i][j] * d[j]
+			}
+
+			s += d[i] * t
+		}
+
+		return math.Sqrt(math.Max(s, 0))
+	}, nil
+}
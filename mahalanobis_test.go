@@ -0,0 +1,50 @@
+package clusters
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMahalanobisDistanceOfUncorrelatedData(t *testing.T) {
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 10000)
+	)
+
+	// independent features with standard deviations of 1 and 10
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{r.NormFloat64(), 10 * r.NormFloat64()}
+	}
+
+	m, e := MahalanobisDistance(d, 0)
+	if e != nil {
+		t.Errorf("Error initializing mahalanobis distance: %s\n", e.Error())
+	}
+
+	if v := m([]float64{0, 0}, []float64{0, 10}); math.Abs(v-1) > 0.05 {
+		t.Errorf("Distance of one standard deviation should be close to 1, it is %f\n", v)
+	}
+
+	if v, w := m([]float64{0, 0}, []float64{1, 0}), m([]float64{0, 0}, []float64{0, 10}); math.Abs(v-w) > 0.05 {
+		t.Errorf("Distances scaled by standard deviation should be equal: %f vs %f\n", v, w)
+	}
+}
+
+func TestMahalanobisDistanceOfSingularCovariance(t *testing.T) {
+	var d = make([][]float64, 100)
+
+	// the second feature is a copy of the first one
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{float64(i), float64(i)}
+	}
+
+	m, e := MahalanobisDistance(d, 0)
+	if e != nil {
+		t.Errorf("Error initializing mahalanobis distance: %s\n", e.Error())
+	}
+
+	if v := m([]float64{0, 0}, []float64{1, 1}); math.IsNaN(v) || math.IsInf(v, 0) {
+		t.Errorf("Distance should be finite, it is %f\n", v)
+	}
+}
@@ -1,7 +1,9 @@
 package clusters
 
 import (
+	"container/heap"
 	"math"
+	"sort"
 	"sync"
 )
 
@@ -10,34 +12,40 @@ type steepDownArea struct {
 	mib        float64
 }
 
-type clusterJob struct {
-	a, b, n int
-}
-
 type opticsClusterer struct {
 	minpts, workers int
 	eps, xi, x      float64
 
 	distance DistanceFunc
 
+	// index narrowing down the search for nearest neighbours, if used
+	index Index
+
+	// index actually used during learning and prediction: either the one above or the grid chosen automatically
+	nn Index
+
+	// precomputed distances between data points, if used
+	precomputed bool
+	dm          DistanceMatrix
+
 	// slices holding the cluster mapping and sizes. Access is synchronized to avoid read during computation.
 	mu   sync.RWMutex
 	a, b []int
 
-	// variables used for concurrent computation of nearest neighbours and producing final mapping
-	l, s, o, f, g int
-	j             chan *rangeJob
-	c             chan *clusterJob
-	m             *sync.Mutex
-	w             *sync.WaitGroup
-	r             *[]int
-	p             []float64
+	// variables used for concurrent computation of nearest neighbours
+	l, s, o, f int
+	j          chan *rangeJob
+	m          *sync.Mutex
+	w          *sync.WaitGroup
+	r          *[]int
+	p          int
 
 	// visited points
 	v []bool
 
-	// reachability distances
+	// reachability distances and points they were reached from
 	re []*pItem
+	pr []int
 
 	// ordered list of points wrt. reachability distance
 	so []int
@@ -47,7 +55,9 @@ type opticsClusterer struct {
 }
 
 // Implementation of OPTICS algorithm with concurrent nearest neighbour computation. The number of goroutines acting concurrently
-// is controlled via workers argument. Passing 0 will result in this number being chosen arbitrarily.
+// is controlled via workers argument. Passing 0 will result in this number being chosen arbitrarily. Clusters are regions
+// of the reachability plot delimited by changes steeper than xi. Of nested clusters the innermost are kept if they split
+// the cluster containing them, the remaining points being noise (-1).
 func OPTICS(minpts int, eps, xi float64, workers int, distance DistanceFunc) (HardClusterer, error) {
 	if minpts < 1 {
 		return nil, errZeroMinpts
@@ -84,6 +94,38 @@ func OPTICS(minpts int, eps, xi float64, workers int, distance DistanceFunc) (Ha
 	}, nil
 }
 
+// Implementation of OPTICS algorithm which searches for nearest neighbours only among the candidates returned by the index,
+// e.g. members of shared canopies. The index is built from the dataset during learning.
+func OPTICSWithIndex(minpts int, eps, xi float64, workers int, distance DistanceFunc, index Index) (HardClusterer, error) {
+	if index == nil {
+		return nil, errNilIndex
+	}
+
+	c, e := OPTICS(minpts, eps, xi, workers, distance)
+	if e != nil {
+		return nil, e
+	}
+
+	c.(*opticsClusterer).index = index
+
+	return c, nil
+}
+
+// Implementation of OPTICS algorithm which learns from precomputed distances between data points, e.g. distances along
+// the road network. Learn expects the dataset to be the square distance matrix, while Predict expects the observation
+// to hold distances to every data point.
+func PrecomputedOPTICS(minpts int, eps, xi float64, workers int) (MatrixClusterer, error) {
+	c, e := OPTICS(minpts, eps, xi, workers, nil)
+	if e != nil {
+		return nil, e
+	}
+
+	d := c.(*opticsClusterer)
+	d.precomputed = true
+
+	return d, nil
+}
+
 func (c *opticsClusterer) IsOnline() bool {
 	return false
 }
@@ -93,43 +135,62 @@ func (c *opticsClusterer) WithOnline(o Online) HardClusterer {
 }
 
 func (c *opticsClusterer) Learn(data [][]float64) error {
+	if c.precomputed {
+		m, e := SquareDistanceMatrix(data)
+		if e != nil {
+			return e
+		}
+
+		return c.LearnMatrix(m)
+	}
+
 	if len(data) == 0 {
 		return errEmptySet
 	}
 
 	c.mu.Lock()
 
-	c.l = len(data)
-	c.s = c.numWorkers()
-	c.o = c.s - 1
-	c.f = c.l / c.s
+	c.nn = c.index
 
-	c.d = data
+	if c.nn == nil && useGrid(data, c.distance) {
+		c.nn = &gridIndex{size: c.eps}
+	}
 
-	c.v = make([]bool, c.l)
-	c.re = make([]*pItem, c.l)
-	c.so = make([]int, 0, c.l)
-	c.a = make([]int, c.l)
-	c.b = make([]int, 0)
+	if c.nn != nil {
+		if e := c.nn.Build(data); e != nil {
+			c.mu.Unlock()
+			return e
+		}
+	}
 
-	c.startNearestWorkers()
+	c.d = data
+	c.dm = nil
+	c.l = len(data)
 
-	c.run()
+	c.learn()
 
-	c.endNearestWorkers()
+	c.mu.Unlock()
 
-	c.v = nil
-	c.p = nil
-	c.r = nil
+	return nil
+}
 
-	c.startClusterWorkers()
+func (c *opticsClusterer) LearnMatrix(m DistanceMatrix) error {
+	if m == nil || m.Len() == 0 {
+		return errEmptySet
+	}
 
-	c.extract()
+	if c.index != nil {
+		return errIndexWithMatrix
+	}
 
-	c.endClusterWorkers()
+	c.mu.Lock()
 
-	c.re = nil
-	c.so = nil
+	c.nn = nil
+	c.d = nil
+	c.dm = m
+	c.l = m.Len()
+
+	c.learn()
 
 	c.mu.Unlock()
 
@@ -151,6 +212,19 @@ func (c *opticsClusterer) Guesses() []int {
 }
 
 func (c *opticsClusterer) Predict(p []float64) int {
+	if c.dm != nil {
+		return c.predictPrecomputed(p)
+	}
+
+	if c.nn != nil {
+		if r := c.nn.Query(p); len(r) > 0 {
+			// candidates from the grid are exact only up to eps
+			if l, m := c.predictIndexed(p, r); !c.grid() || m < c.eps {
+				return c.a[l]
+			}
+		}
+	}
+
 	var (
 		l int
 		d float64
@@ -171,6 +245,40 @@ func (c *opticsClusterer) Online(observations chan []float64, done chan struct{}
 	return nil
 }
 
+// private
+func (c *opticsClusterer) learn() {
+	c.s = numWorkers(c.l, c.workers)
+	c.o = c.s - 1
+	c.f = c.l / c.s
+
+	c.v = make([]bool, c.l)
+	c.re = make([]*pItem, c.l)
+	c.pr = make([]int, c.l)
+	c.so = make([]int, 0, c.l)
+	c.a = make([]int, c.l)
+	c.b = make([]int, 0)
+
+	// neighbours are looked up in the index instead of scanning the dataset concurrently
+	if c.nn != nil {
+		c.run()
+	} else {
+		c.startNearestWorkers()
+
+		c.run()
+
+		c.endNearestWorkers()
+	}
+
+	c.v = nil
+	c.r = nil
+
+	c.extract()
+
+	c.re = nil
+	c.pr = nil
+	c.so = nil
+}
+
 func (c *opticsClusterer) run() {
 	var (
 		l       int
@@ -191,13 +299,13 @@ func (c *opticsClusterer) run() {
 
 		c.so = append(c.so, i)
 
-		if d = c.coreDistance(i, l, ns); d != 0 {
+		if d = c.coreDistance(i, l, ns); d >= 0 {
 			q = newPriorityQueue(l)
 
 			c.update(i, d, l, ns, &q)
 
 			for q.NotEmpty() {
-				p = q.Pop().(*pItem)
+				p = heap.Pop(&q).(*pItem)
 
 				c.nearest(p.v, &l, &nss)
 
@@ -205,7 +313,7 @@ func (c *opticsClusterer) run() {
 
 				c.so = append(c.so, p.v)
 
-				if d = c.coreDistance(p.v, l, nss); d != 0 {
+				if d = c.coreDistance(p.v, l, nss); d >= 0 {
 					c.update(p.v, d, l, nss, &q)
 				}
 			}
@@ -213,26 +321,27 @@ func (c *opticsClusterer) run() {
 	}
 }
 
+// Distance to the minpts-th closest neighbour, the point itself included, or -1 if the point is not core
 func (c *opticsClusterer) coreDistance(p int, l int, r []int) float64 {
 	if l < c.minpts {
-		return 0
+		return -1
 	}
 
-	var d, m float64 = 0, c.distance(c.d[p], c.d[r[0]])
+	var d = make([]float64, l)
 
-	for i := 1; i < l; i++ {
-		if d = c.distance(c.d[p], c.d[r[i]]); d > m {
-			m = d
-		}
+	for i := 0; i < l; i++ {
+		d[i] = c.between(p, r[i])
 	}
 
-	return m
+	sort.Float64s(d)
+
+	return d[c.minpts-1]
 }
 
 func (c *opticsClusterer) update(p int, d float64, l int, r []int, q *priorityQueue) {
 	for i := 0; i < l; i++ {
 		if !c.v[r[i]] {
-			m := math.Max(d, c.distance(c.d[p], c.d[r[i]]))
+			m := math.Max(d, c.between(p, r[i]))
 
 			if c.re[r[i]] == nil {
 				item := &pItem{
@@ -241,229 +350,231 @@ func (c *opticsClusterer) update(p int, d float64, l int, r []int, q *priorityQu
 				}
 
 				c.re[r[i]] = item
+				c.pr[r[i]] = p
 
 				q.Push(item)
 			} else if m < c.re[r[i]].p {
 				q.Update(c.re[r[i]], c.re[r[i]].v, m)
+				c.pr[r[i]] = p
 			}
 		}
 	}
 }
 
+/* Clusters are extracted from the reachability plot using the steep areas as in "OPTICS: Ordering Points To Identify
+ * the Clustering Structure" (Ankerst et al.). Points starting a new ordered run have undefined reachability, treated
+ * as infinite, and so does the end of the plot. Of nested clusters the innermost ones are kept if they split the cluster
+ * containing them, the remaining points are noise */
 func (c *opticsClusterer) extract() {
 	var (
-		i, k, e, ue, cs, ce, s, p int = 0, 1, 0, 0, 0, 0, 0, 0
-		mib, d                    float64
-		areas                     []*steepDownArea = make([]*steepDownArea, 0)
-		clusters                  map[int]bool     = make(map[int]bool)
+		n        = len(c.so)
+		i, k     = 0, 1
+		mib      float64
+		r        = make([]float64, n+1)
+		up, down = make([]bool, n), make([]bool, n)
+		lt, gt   = make([]bool, n), make([]bool, n)
+		areas    = make([]*steepDownArea, 0)
+		clusters = make([][2]int, 0)
+		l        = make([]int, n)
 	)
 
-	// first and last points are not assigned the reachability distance, so exclude them from calculations
-	c.so = c.so[1 : len(c.so)-2]
-	c.g = len(c.so) - 2
+	for j, p := range c.so {
+		if c.re[p] != nil {
+			r[j] = c.re[p].p
+		} else {
+			r[j] = math.Inf(1)
+		}
+	}
 
-	for i < len(c.so)-1 {
-		mib = math.Max(mib, c.re[c.so[i]].p)
+	r[n] = math.Inf(1)
 
-		if c.isSteepDown(i, &e) {
-			as := areas[:0]
-			for j := 0; j < len(areas); j++ {
-				if c.re[c.so[areas[j].start]].p*c.x < mib {
-					continue
-				}
+	for j := 0; j < n; j++ {
+		up[j] = r[j] <= r[j+1]*c.x
+		down[j] = r[j]*c.x >= r[j+1]
+		lt[j] = r[j] < r[j+1]
+		gt[j] = r[j] > r[j+1]
+	}
 
-				as = append(as, &steepDownArea{
-					start: areas[j].start,
-					end:   areas[j].end,
-					mib:   math.Max(areas[j].mib, mib),
-				})
-			}
-			areas = as
+	for s := 0; s < n; s++ {
+		if s < i || !up[s] && !down[s] {
+			continue
+		}
+
+		for j := i; j <= s; j++ {
+			mib = math.Max(mib, r[j])
+		}
+
+		areas = c.filter(areas, mib, r)
+
+		if down[s] {
+			e := c.extend(down, lt, s)
 
 			areas = append(areas, &steepDownArea{
-				start: i,
+				start: s,
 				end:   e,
 			})
 
 			i = e + 1
-			mib = c.re[c.so[i]].p
-		} else if c.isSteepUp(i, &e) {
-			ue = e + 1
-
-			as := areas[:0]
-			for j := 0; j < len(areas); j++ {
-				if c.re[c.so[areas[j].start]].p*c.x < mib {
-					continue
-				}
-
-				as = append(as, &steepDownArea{
-					start: areas[j].start,
-					end:   areas[j].end,
-					mib:   math.Max(areas[j].mib, mib),
-				})
-			}
-			areas = as
-
-			for j := 0; j < len(areas); j++ {
-				if c.re[c.so[ue]].p*c.x < areas[j].mib {
-					continue
-				}
-
-				d = (c.re[c.so[areas[j].start]].p - c.re[c.so[ue]].p) / c.re[c.so[areas[j].start]].p
-
-				if math.Abs(d) <= c.xi {
-					cs = areas[j].start
-					ce = ue
-				} else if d > c.xi {
-					for k := areas[j].end; k > areas[j].end; k-- {
-						if math.Abs((c.re[c.so[k]].p-c.re[c.so[ue]].p)/c.re[c.so[k]].p) <= c.xi {
-							cs = k
-							break
-						}
-					}
-					ce = ue
-				} else {
-					cs = areas[j].start
-					for k := i; k < e; k++ {
-						if math.Abs((c.re[c.so[k]].p-c.re[c.so[i]].p)/c.re[c.so[k]].p) <= c.xi {
-							ce = k
-							break
-						}
-					}
-				}
-
-				p = ce - cs
+			mib = r[i]
 
-				if p < c.minpts {
-					continue
-				}
-
-				s = ce + cs
+			continue
+		}
 
-				if !clusters[s] {
-					clusters[s] = true
+		var (
+			e = c.extend(up, gt, s)
+			u = make([][2]int, 0)
+		)
 
-					c.b = append(c.b, 0)
-					c.b[k] = p
+		i = e + 1
+		mib = r[i]
 
-					c.w.Add(1)
+		for _, a := range areas {
+			var (
+				cs, ce = a.start, e
+				m      = r[a.start]
+			)
 
-					c.c <- &clusterJob{
-						a: cs,
-						b: ce,
-						n: k,
-					}
+			if r[ce+1]*c.x < a.mib {
+				continue
+			}
 
-					k++
+			// the start of the cluster is moved forward or its end backward so that both have similar reachability
+			if m*c.x >= r[ce+1] {
+				for r[cs+1] > r[ce+1] && cs < a.end {
+					cs++
+				}
+			} else if r[ce+1]*c.x >= m {
+				for r[ce-1] > m && ce > s {
+					ce--
 				}
 			}
 
-			i = ue
-			mib = c.re[c.so[i]].p
-		} else {
-			i++
-		}
-	}
+			var ok bool
 
-	c.w.Wait()
-}
-
-func (c *opticsClusterer) isSteepDown(i int, e *int) bool {
-	if c.re[c.so[i]].p*c.x > c.re[c.so[i+1]].p {
-		return false
-	}
-
-	var counter, j int = 0, i
+			if cs, ce, ok = c.correct(r, cs, ce); !ok {
+				continue
+			}
 
-	*e = j
+			if ce-cs+1 < c.minpts || cs > a.end || ce < s {
+				continue
+			}
 
-	for {
-		if j > c.g {
-			break
+			u = append(u, [2]int{cs, ce})
 		}
 
-		if c.re[c.so[j]].p < c.re[c.so[j+1]].p {
-			break
+		// clusters ending at the same steep up area are nested, so the innermost one goes first
+		for j := len(u) - 1; j >= 0; j-- {
+			clusters = append(clusters, u[j])
 		}
+	}
 
-		if c.re[c.so[j]].p*c.x <= c.re[c.so[j+1]].p {
-			*e = j
-			counter = 0
-		} else {
-			counter++
-		}
+	for j := 0; j < n; j++ {
+		l[j] = -1
+	}
 
-		if counter > c.minpts {
-			break
+	for _, b := range c.innermost(clusters) {
+		for j := b[0]; j <= b[1]; j++ {
+			l[j] = k
 		}
 
-		j++
+		c.b = append(c.b, b[1]-b[0]+1)
+
+		k++
 	}
 
-	return *e != i
+	for j, p := range c.so {
+		c.a[p] = l[j]
+	}
 }
 
-func (c *opticsClusterer) isSteepUp(i int, e *int) bool {
-	if c.re[c.so[i]].p > c.re[c.so[i+1]].p*c.x {
-		return false
+// Steep down areas which can still start a cluster, with maximum in-between reachability updated
+func (c *opticsClusterer) filter(areas []*steepDownArea, mib float64, r []float64) []*steepDownArea {
+	if math.IsInf(mib, 1) {
+		return areas[:0]
 	}
 
-	var counter, j int = 0, i
-
-	*e = j
+	var as = areas[:0]
 
-	for {
-		if j > c.g {
-			break
+	for _, a := range areas {
+		if mib <= r[a.start]*c.x {
+			a.mib = math.Max(a.mib, mib)
+			as = append(as, a)
 		}
+	}
 
-		if c.re[c.so[j]].p > c.re[c.so[j+1]].p {
-			break
-		}
+	return as
+}
 
-		if c.re[c.so[j]].p <= c.re[c.so[j+1]].p*c.x {
-			*e = j
-			counter = 0
-		} else {
-			counter++
-		}
+// End of the steep area starting at s, which may contain at most minpts consecutive points not steep in its direction
+// and ends before the first point going in the opposite direction
+func (c *opticsClusterer) extend(steep, opposite []bool, s int) int {
+	var counter, e = 0, s
 
-		if counter > c.minpts {
+	for j := s; j < len(steep); j++ {
+		if steep[j] {
+			counter = 0
+			e = j
+		} else if opposite[j] {
+			break
+		} else if counter++; counter > c.minpts {
 			break
 		}
-
-		j++
 	}
 
-	return *e != i
+	return e
 }
 
-func (c *opticsClusterer) startClusterWorkers() {
-	c.c = make(chan *clusterJob, c.l)
-
-	c.w = &sync.WaitGroup{}
+// Moves the end of the cluster backward until the point reached from outside of the cluster is excluded
+// ("Improving the Cluster Structure Extracted from OPTICS Plots", Schubert et al.)
+func (c *opticsClusterer) correct(r []float64, cs, ce int) (int, int, bool) {
+	for ; cs < ce; ce-- {
+		if r[cs] > r[ce] {
+			return cs, ce, true
+		}
 
-	for i := 0; i < c.s; i++ {
-		go c.clusterWorker()
+		for j := cs; j < ce; j++ {
+			if c.so[j] == c.pr[c.so[ce]] {
+				return cs, ce, true
+			}
+		}
 	}
-}
 
-func (c *opticsClusterer) endClusterWorkers() {
-	close(c.c)
-
-	c.c = nil
-
-	c.w = nil
+	return cs, ce, false
 }
 
-func (c *opticsClusterer) clusterWorker() {
-	for j := range c.c {
-		for i := j.a; i < j.b; i++ {
-			c.a[i] = j.n
+/* Innermost clusters, except that a cluster replaces those nested in it unless there are several of them holding most
+ * of its points, since a single denser region or a few small ones are not a split of the cluster. Nested clusters are
+ * expected to precede the clusters containing them */
+func (c *opticsClusterer) innermost(clusters [][2]int) [][2]int {
+	var r = make([][2]int, 0)
+
+	for _, b := range clusters {
+		var (
+			rs   = make([][2]int, 0, len(r)+1)
+			n, m int
+			ok   = true
+		)
+
+		for _, a := range r {
+			if a[0] >= b[0] && a[1] <= b[1] {
+				n++
+				m += a[1] - a[0] + 1
+			} else if a[0] <= b[1] && a[1] >= b[0] {
+				ok = false
+				break
+			} else {
+				rs = append(rs, a)
+			}
 		}
 
-		c.w.Done()
+		if !ok || n > 1 && 2*m > b[1]-b[0]+1 {
+			continue
+		}
+
+		r = append(rs, b)
 	}
+
+	return r
 }
 
 /* Divide work among c.s workers, where c.s is determined
@@ -475,20 +586,25 @@ func (c *opticsClusterer) nearest(p int, l *int, r *[]int) {
 
 	*r = (*r)[:0]
 
-	c.p = c.d[p]
+	if c.nn != nil {
+		c.nearestIndexed(p, l, r)
+		return
+	}
+
+	c.p = p
 	c.r = r
 
 	c.w.Add(c.s)
 
-	for i := 0; i < c.l; i += c.f {
-		if c.l-i <= c.f {
-			b = c.l - 1
+	for i := 0; i < c.s; i++ {
+		if i == c.o {
+			b = c.l
 		} else {
-			b = i + c.f
+			b = (i + 1) * c.f
 		}
 
 		c.j <- &rangeJob{
-			a: i,
+			a: i * c.f,
 			b: b,
 		}
 	}
@@ -498,6 +614,52 @@ func (c *opticsClusterer) nearest(p int, l *int, r *[]int) {
 	*l = len(*r)
 }
 
+// Candidates returned by the index are expected to be few, so they are scanned without the workers
+func (c *opticsClusterer) nearestIndexed(p int, l *int, r *[]int) {
+	for _, i := range c.nn.Query(c.d[p]) {
+		if c.distance(c.d[p], c.d[i]) < c.eps {
+			*r = append(*r, i)
+		}
+	}
+
+	*l = len(*r)
+}
+
+func (c *opticsClusterer) predictIndexed(p []float64, r []int) (int, float64) {
+	var (
+		l int = r[0]
+		d float64
+		m float64 = c.distance(p, c.d[r[0]])
+	)
+
+	for _, i := range r[1:] {
+		if d = c.distance(p, c.d[i]); d < m {
+			m = d
+			l = i
+		}
+	}
+
+	return l, m
+}
+
+func (c *opticsClusterer) grid() bool {
+	_, ok := c.nn.(*gridIndex)
+	return ok
+}
+
+// observation holds distances to every data point
+func (c *opticsClusterer) predictPrecomputed(p []float64) int {
+	var l int
+
+	for i := 1; i < len(p) && i < c.l; i++ {
+		if p[i] < p[l] {
+			l = i
+		}
+	}
+
+	return c.a[l]
+}
+
 func (c *opticsClusterer) startNearestWorkers() {
 	c.j = make(chan *rangeJob, c.l)
 
@@ -505,7 +667,7 @@ func (c *opticsClusterer) startNearestWorkers() {
 	c.w = &sync.WaitGroup{}
 
 	for i := 0; i < c.s; i++ {
-		go c.nearestWorker()
+		go c.nearestWorker(c.j)
 	}
 }
 
@@ -518,10 +680,10 @@ func (c *opticsClusterer) endNearestWorkers() {
 	c.w = nil
 }
 
-func (c *opticsClusterer) nearestWorker() {
-	for j := range c.j {
+func (c *opticsClusterer) nearestWorker(jobs chan *rangeJob) {
+	for j := range jobs {
 		for i := j.a; i < j.b; i++ {
-			if c.distance(c.p, c.d[i]) < c.eps {
+			if c.between(c.p, i) < c.eps {
 				c.m.Lock()
 				*c.r = append(*c.r, i)
 				c.m.Unlock()
@@ -532,26 +694,10 @@ func (c *opticsClusterer) nearestWorker() {
 	}
 }
 
-func (c *opticsClusterer) numWorkers() int {
-	var b int
-
-	if c.l < 1000 {
-		b = 1
-	} else if c.l < 10000 {
-		b = 10
-	} else if c.l < 100000 {
-		b = 100
-	} else {
-		b = 1000
-	}
-
-	if c.workers == 0 {
-		return b
-	}
-
-	if c.workers < b {
-		return c.workers
+func (c *opticsClusterer) between(p, q int) float64 {
+	if c.dm != nil {
+		return c.dm.At(p, q)
 	}
 
-	return b
+	return c.distance(c.d[p], c.d[q])
 }
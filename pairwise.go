@@ -0,0 +1,209 @@
+package clusters
+
+import (
+	"runtime"
+	"sync"
+)
+
+const (
+	// size of square blocks of the distance matrix computed by a worker at once
+	pairwiseBlock = 64
+
+	// size of float64 and of slice header in bytes
+	floatSize  = 8
+	headerSize = 24
+)
+
+// struct denoting start indices of rows and columns of a block of distance matrix to be computed by workers
+type blockJob struct {
+	i, j int
+}
+
+// PairwiseDistances computes the full n x n matrix of distances between data points. The matrix is divided into blocks
+// computed concurrently by a number of workers, passing 0 will result in this number being the number of CPUs.
+// Budget limits the memory taken by the result in bytes, an error is returned if it would be exceeded. Passing 0
+// disables the limit.
+func PairwiseDistances(data [][]float64, distance DistanceFunc, workers int, budget int64) ([][]float64, error) {
+	if len(data) == 0 {
+		return nil, errEmptySet
+	}
+
+	if workers < 0 {
+		return nil, errZeroWorkers
+	}
+
+	var n = int64(len(data))
+
+	if !withinBudget(budget, n, n) {
+		return nil, errMemoryBudget
+	}
+
+	var (
+		d = distanceOrDefault(distance)
+		r = make([][]float64, len(data))
+	)
+
+	for i := 0; i < len(data); i++ {
+		r[i] = make([]float64, len(data))
+	}
+
+	// only blocks on and above the diagonal are computed and mirrored
+	computeBlocks(len(data), len(data), true, workers, func(i, j int) {
+		v := d(data[i], data[j])
+
+		r[i][j] = v
+		r[j][i] = v
+	})
+
+	return r, nil
+}
+
+// CondensedDistances computes the upper triangle of the matrix of distances between data points (without the diagonal)
+// stored row by row, as expected by CondensedDistanceMatrix. It takes about half of the memory of PairwiseDistances.
+// Workers and budget have the same meaning as in PairwiseDistances.
+func CondensedDistances(data [][]float64, distance DistanceFunc, workers int, budget int64) ([]float64, error) {
+	if len(data) < 2 {
+		return nil, errNotEnoughData
+	}
+
+	if workers < 0 {
+		return nil, errZeroWorkers
+	}
+
+	var n = int64(len(data))
+
+	if budget > 0 && (n*(n-1)/2 > budget/floatSize) {
+		return nil, errMemoryBudget
+	}
+
+	var (
+		d = distanceOrDefault(distance)
+		l = len(data)
+		r = make([]float64, l*(l-1)/2)
+	)
+
+	computeBlocks(l, l, true, workers, func(i, j int) {
+		if i != j {
+			r[l*i-i*(i+1)/2+j-i-1] = d(data[i], data[j])
+		}
+	})
+
+	return r, nil
+}
+
+// CrossDistances computes the matrix of distances between every point of a (rows) and every point of b (columns).
+// Workers and budget have the same meaning as in PairwiseDistances.
+func CrossDistances(a, b [][]float64, distance DistanceFunc, workers int, budget int64) ([][]float64, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return nil, errEmptySet
+	}
+
+	if workers < 0 {
+		return nil, errZeroWorkers
+	}
+
+	if !withinBudget(budget, int64(len(a)), int64(len(b))) {
+		return nil, errMemoryBudget
+	}
+
+	var (
+		d = distanceOrDefault(distance)
+		r = make([][]float64, len(a))
+	)
+
+	for i := 0; i < len(a); i++ {
+		r[i] = make([]float64, len(b))
+	}
+
+	computeBlocks(len(a), len(b), false, workers, func(i, j int) {
+		r[i][j] = d(a[i], b[j])
+	})
+
+	return r, nil
+}
+
+// private
+func distanceOrDefault(distance DistanceFunc) DistanceFunc {
+	if distance != nil {
+		return distance
+	}
+
+	return EuclideanDistance
+}
+
+func withinBudget(budget, rows, columns int64) bool {
+	if budget <= 0 {
+		return true
+	}
+
+	if rows*headerSize > budget {
+		return false
+	}
+
+	return rows <= (budget-rows*headerSize)/floatSize/columns
+}
+
+/* Divide the matrix into square blocks and let the workers compute them, so that the rows and columns of a block
+ * stay in cache. For symmetric matrices only the blocks on and above the diagonal are scheduled, and within
+ * diagonal blocks only the cells with i <= j are computed. */
+func computeBlocks(rows, columns int, symmetric bool, workers int, f func(i, j int)) {
+	var (
+		w  sync.WaitGroup
+		js = make(chan *blockJob, pairwiseBlock)
+	)
+
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+
+	w.Add(workers)
+
+	for k := 0; k < workers; k++ {
+		go func() {
+			defer w.Done()
+
+			for b := range js {
+				var ei, ej = b.i + pairwiseBlock, b.j + pairwiseBlock
+
+				if ei > rows {
+					ei = rows
+				}
+
+				if ej > columns {
+					ej = columns
+				}
+
+				for i := b.i; i < ei; i++ {
+					sj := b.j
+
+					if symmetric && sj < i {
+						sj = i
+					}
+
+					for j := sj; j < ej; j++ {
+						f(i, j)
+					}
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < rows; i += pairwiseBlock {
+		j := 0
+
+		if symmetric {
+			j = i
+		}
+
+		for ; j < columns; j += pairwiseBlock {
+			js <- &blockJob{
+				i: i,
+				j: j,
+			}
+		}
+	}
+
+	close(js)
+
+	w.Wait()
+}
@@ -0,0 +1,55 @@
+package clusters
+
+import (
+	"testing"
+)
+
+func TestPairwiseDistances(t *testing.T) {
+	var d = blobs(2, 150, 1)
+
+	r, e := PairwiseDistances(d, EuclideanDistance, 4, 0)
+	if e != nil {
+		t.Errorf("Error computing distances: %s\n", e.Error())
+	}
+
+	s, e := CondensedDistances(d, EuclideanDistance, 4, 0)
+	if e != nil {
+		t.Errorf("Error computing distances: %s\n", e.Error())
+	}
+
+	m, e := CondensedDistanceMatrix(s)
+	if e != nil {
+		t.Errorf("Error initializing distance matrix: %s\n", e.Error())
+	}
+
+	x, e := CrossDistances(d[:100], d, EuclideanDistance, 0, 0)
+	if e != nil {
+		t.Errorf("Error computing distances: %s\n", e.Error())
+	}
+
+	for i := 0; i < len(d); i++ {
+		for j := 0; j < len(d); j++ {
+			v := EuclideanDistance(d[i], d[j])
+
+			if r[i][j] != v || m.At(i, j) != v {
+				t.Fatalf("Distance between %d and %d mismatch: %f, %f vs %f\n", i, j, r[i][j], m.At(i, j), v)
+			}
+
+			if i < 100 && x[i][j] != v {
+				t.Fatalf("Cross distance between %d and %d mismatch: %f vs %f\n", i, j, x[i][j], v)
+			}
+		}
+	}
+}
+
+func TestPairwiseDistancesMemoryBudget(t *testing.T) {
+	var d = blobs(2, 150, 1)
+
+	if _, e := PairwiseDistances(d, EuclideanDistance, 0, 300*300*8); e != errMemoryBudget {
+		t.Error("Full matrix should exceed the memory budget")
+	}
+
+	if _, e := CondensedDistances(d, EuclideanDistance, 0, 300*300*8); e != nil {
+		t.Error("Condensed matrix should fit in the memory budget")
+	}
+}
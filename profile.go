@@ -0,0 +1,229 @@
+package clusters
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// FeatureProfile holds statistics of a single feature of members of a cluster
+type FeatureProfile struct {
+	Mean, Std, Min, Max float64
+
+	// Quantiles holds the empirical 25th, 50th and 75th percentile
+	Quantiles [3]float64
+
+	// Score is the standardized mean difference (Cohen's d) between members of the cluster and the remaining data points
+	Score float64
+}
+
+// ClusterProfile holds statistics of a single cluster
+type ClusterProfile struct {
+	Label, Count int
+
+	// Centroid is the mean of members, while Medoid is the index of the member with the lowest sum of distances to other members
+	Centroid []float64
+	Medoid   int
+
+	// Features holds statistics of respective features
+	Features []FeatureProfile
+
+	// Radius is the largest distance of a member to the centroid, while Diameter is the largest distance between two members
+	Radius, Diameter float64
+
+	// Distinguishing holds indices of features ordered from the one which distinguishes the cluster from the remaining data
+	// points the most, that is with the highest absolute score
+	Distinguishing []int
+}
+
+// Profiles returns statistics of clusters of the partition of the dataset given by the mapping, e.g. Guesses of a clusterer,
+// in ascending order of their labels. Noise (-1) is excluded both from clusters and from the remaining data points clusters
+// are compared with. Pass nil to use EuclideanDistance for medoids, radii and diameters.
+func Profiles(data [][]float64, mapping []int, distance DistanceFunc) ([]ClusterProfile, error) {
+	if len(data) == 0 {
+		return nil, errEmptySet
+	}
+
+	if len(data) != len(mapping) {
+		return nil, errMismatchedLabels
+	}
+
+	for i := 1; i < len(data); i++ {
+		if len(data[i]) != len(data[0]) {
+			return nil, errMismatchedDimensions
+		}
+	}
+
+	var (
+		f = distanceOrDefault(distance)
+		k = make(map[int][]int)
+		l []int
+	)
+
+	for i, c := range mapping {
+		if c == -1 {
+			continue
+		}
+
+		if _, ok := k[c]; !ok {
+			l = append(l, c)
+		}
+
+		k[c] = append(k[c], i)
+	}
+
+	if len(l) == 0 {
+		return nil, errNotEnoughClusters
+	}
+
+	sort.Ints(l)
+
+	var r = make([]ClusterProfile, len(l))
+
+	for i, c := range l {
+		r[i] = profile(data, mapping, c, k[c], f)
+	}
+
+	return r, nil
+}
+
+// private
+
+// indices of features sorted by absolute score in descending order
+type byScore struct {
+	i []int
+	f []FeatureProfile
+}
+
+func (s *byScore) Len() int { return len(s.i) }
+
+func (s *byScore) Less(a, b int) bool {
+	return math.Abs(s.f[s.i[a]].Score) > math.Abs(s.f[s.i[b]].Score)
+}
+
+func (s *byScore) Swap(a, b int) {
+	s.i[a], s.i[b] = s.i[b], s.i[a]
+}
+
+func profile(data [][]float64, mapping []int, label int, members []int, distance DistanceFunc) ClusterProfile {
+	var (
+		n = len(data[0])
+		p = ClusterProfile{
+			Label:          label,
+			Count:          len(members),
+			Centroid:       make([]float64, n),
+			Features:       make([]FeatureProfile, n),
+			Distinguishing: make([]int, n),
+		}
+		x = make([]float64, len(members))
+		y = make([]float64, 0, len(data)-len(members))
+	)
+
+	for j := 0; j < n; j++ {
+		y = y[:0]
+
+		for i, m := range members {
+			x[i] = data[m][j]
+		}
+
+		for i := 0; i < len(data); i++ {
+			if mapping[i] != -1 && mapping[i] != label {
+				y = append(y, data[i][j])
+			}
+		}
+
+		sort.Float64s(x)
+
+		p.Centroid[j] = stat.Mean(x, nil)
+		p.Features[j] = FeatureProfile{
+			Mean: p.Centroid[j],
+			Min:  x[0],
+			Max:  x[len(x)-1],
+			Quantiles: [3]float64{
+				stat.Quantile(0.25, stat.Empirical, x, nil),
+				stat.Quantile(0.5, stat.Empirical, x, nil),
+				stat.Quantile(0.75, stat.Empirical, x, nil),
+			},
+			Score: meanDifference(x, y),
+		}
+
+		if len(x) > 1 {
+			p.Features[j].Std = stat.StdDev(x, nil)
+		}
+
+		p.Distinguishing[j] = j
+	}
+
+	sort.Stable(&byScore{
+		i: p.Distinguishing,
+		f: p.Features,
+	})
+
+	// sums of distances of members to other members
+	var s = make([]float64, len(members))
+
+	for a := 0; a < len(members); a++ {
+		p.Radius = math.Max(p.Radius, distance(data[members[a]], p.Centroid))
+
+		for b := a + 1; b < len(members); b++ {
+			d := distance(data[members[a]], data[members[b]])
+
+			s[a] += d
+			s[b] += d
+
+			p.Diameter = math.Max(p.Diameter, d)
+		}
+	}
+
+	var m int
+
+	for a := 1; a < len(members); a++ {
+		if s[a] < s[m] {
+			m = a
+		}
+	}
+
+	p.Medoid = members[m]
+
+	return p
+}
+
+// Standardized mean difference of two samples using their pooled standard deviation
+func meanDifference(x, y []float64) float64 {
+	if len(y) == 0 {
+		return 0
+	}
+
+	var (
+		mx, vx = stat.MeanVariance(x, nil)
+		my, vy = stat.MeanVariance(y, nil)
+		nx, ny = float64(len(x)), float64(len(y))
+		d      = mx - my
+	)
+
+	// variance of a single value is undefined
+	if len(x) == 1 {
+		vx = 0
+	}
+
+	if len(y) == 1 {
+		vy = 0
+	}
+
+	if nx+ny <= 2 {
+		return 0
+	}
+
+	s := math.Sqrt(((nx-1)*vx + (ny-1)*vy) / (nx + ny - 2))
+
+	if s == 0 {
+		if d == 0 {
+			return 0
+		}
+
+		return math.Copysign(math.Inf(1), d)
+	}
+
+	return d / s
+}
@@ -0,0 +1,50 @@
+package clusters
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	var (
+		d = [][]float64{{0, 5}, {2, 5}, {1, 6}, {10, 5}, {12, 6}, {11, 5}, {100, 100}}
+		m = []int{2, 2, 2, 1, 1, 1, -1}
+	)
+
+	p, e := Profiles(d, m, nil)
+	if e != nil {
+		t.Errorf("Error computing profiles: %s\n", e.Error())
+	}
+
+	if len(p) != 2 || p[0].Label != 1 || p[1].Label != 2 {
+		t.Errorf("Profiles should be ordered by labels: %v\n", p)
+	}
+
+	var c = p[1]
+
+	if c.Count != 3 || c.Centroid[0] != 1 || math.Abs(c.Centroid[1]-16.0/3) > 1e-9 {
+		t.Errorf("Count and centroid are invalid: %d %v\n", c.Count, c.Centroid)
+	}
+
+	// {1, 6} has the lowest sum of distances to other members
+	if c.Medoid != 2 {
+		t.Errorf("Medoid should be 2, it is %d\n", c.Medoid)
+	}
+
+	if f := c.Features[0]; f.Min != 0 || f.Max != 2 || f.Std != 1 || f.Quantiles[1] != 1 {
+		t.Errorf("Statistics of the first feature are invalid: %+v\n", f)
+	}
+
+	if c.Diameter != 2 || math.Abs(c.Radius-math.Sqrt(1+1.0/9)) > 1e-9 {
+		t.Errorf("Diameter and radius are invalid: %f %f\n", c.Diameter, c.Radius)
+	}
+
+	// clusters differ in the first feature only, while noise is excluded from the comparison
+	if c.Distinguishing[0] != 0 || c.Features[0].Score >= 0 || math.Abs(c.Features[1].Score) > 1e-9 {
+		t.Errorf("The first feature should distinguish the cluster: %v %+v\n", c.Distinguishing, c.Features)
+	}
+
+	if _, e = Profiles(d, m[1:], nil); e == nil {
+		t.Error("Mismatched labels should result in an error\n")
+	}
+}
@@ -0,0 +1,192 @@
+package clusters
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+const (
+	// number of data points in a single job of workers computing silhouette coefficients
+	silhouetteBlock = 64
+)
+
+// Silhouette holds silhouette coefficients of a partition of the dataset
+type Silhouette struct {
+	// Points holds coefficients of respective data points, in the order of Guesses, or NaN for noise (-1)
+	Points []float64
+
+	// Clusters holds mean coefficients of clusters by their labels
+	Clusters map[int]float64
+
+	// Mean is the mean coefficient of all data points but noise
+	Mean float64
+}
+
+// Silhouettes returns silhouette coefficients ("Silhouettes: a graphical aid to the interpretation and validation
+// of cluster analysis", Rousseeuw) of data points and clusters of the partition given by the mapping, e.g. Guesses
+// of a clusterer. Coefficients close to -1 denote points which are likely assigned to a wrong cluster. Noise (-1)
+// is skipped and members of singleton clusters score 0. Distances between data points are computed once with CondensedDistances,
+// whose memory is limited by budget in bytes (0 disables the limit), and then the coefficients are computed concurrently
+// by a number of workers, passing 0 will result in this number being the number of CPUs. Pass nil to use EuclideanDistance.
+func Silhouettes(data [][]float64, mapping []int, distance DistanceFunc, workers int, budget int64) (*Silhouette, error) {
+	if len(data) == 0 {
+		return nil, errEmptySet
+	}
+
+	if len(data) != len(mapping) {
+		return nil, errMismatchedLabels
+	}
+
+	if workers < 0 {
+		return nil, errZeroWorkers
+	}
+
+	var (
+		k, s = clusterSizes(mapping)
+		r    = &Silhouette{
+			Points:   make([]float64, len(data)),
+			Clusters: make(map[int]float64, len(s)),
+		}
+		j  = make(chan *rangeJob, len(data)/silhouetteBlock+1)
+		wg sync.WaitGroup
+	)
+
+	if len(s) < 2 {
+		return nil, errNotEnoughClusters
+	}
+
+	c, e := CondensedDistances(data, distance, workers, budget)
+	if e != nil {
+		return nil, e
+	}
+
+	m, e := CondensedDistanceMatrix(c)
+	if e != nil {
+		return nil, e
+	}
+
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+
+	for i := 0; i < len(data); i += silhouetteBlock {
+		var b = i + silhouetteBlock
+
+		if b > len(data) {
+			b = len(data)
+		}
+
+		j <- &rangeJob{
+			a: i,
+			b: b,
+		}
+	}
+
+	close(j)
+
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			var d = make([]float64, len(s))
+
+			for b := range j {
+				for i := b.a; i < b.b; i++ {
+					if mapping[i] == -1 {
+						r.Points[i] = math.NaN()
+					} else {
+						r.Points[i] = pointSilhouette(mapping, i, k, s, m.At, d)
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var n int
+
+	for i, v := range r.Points {
+		if mapping[i] != -1 {
+			r.Clusters[mapping[i]] += v
+			r.Mean += v
+			n++
+		}
+	}
+
+	for l, i := range k {
+		r.Clusters[l] /= float64(s[i])
+	}
+
+	r.Mean /= float64(n)
+
+	return r, nil
+}
+
+// private
+
+// Cluster labels mapped to consecutive numbers and sizes of respective clusters, with noise (-1) excluded
+func clusterSizes(mapping []int) (map[int]int, []int) {
+	var (
+		k = make(map[int]int)
+		s []int
+	)
+
+	for _, l := range mapping {
+		if l == -1 {
+			continue
+		}
+
+		if _, ok := k[l]; !ok {
+			k[l] = len(s)
+			s = append(s, 0)
+		}
+
+		s[k[l]]++
+	}
+
+	return k, s
+}
+
+/* Silhouette coefficient of a point which is not noise, that is (b - a) / max(a, b), where a is its mean distance
+ * to other members of its cluster and b the lowest mean distance to members of any other cluster. Members of singleton
+ * clusters score 0. Distances are looked up by indices of data points and slice d is used to sum them by clusters */
+func pointSilhouette(mapping []int, i int, k map[int]int, s []int, between func(i, j int) float64, d []float64) float64 {
+	var a, b float64
+
+	for j := range d {
+		d[j] = 0
+	}
+
+	for j := 0; j < len(mapping); j++ {
+		if mapping[j] != -1 && j != i {
+			d[k[mapping[j]]] += between(i, j)
+		}
+	}
+
+	l := k[mapping[i]]
+
+	if s[l] == 1 {
+		return 0
+	}
+
+	a = d[l] / float64(s[l]-1)
+	b = -1
+
+	for j := range d {
+		if j != l && (b < 0 || d[j]/float64(s[j]) < b) {
+			b = d[j] / float64(s[j])
+		}
+	}
+
+	if a < b {
+		return 1 - a/b
+	} else if a > b {
+		return b/a - 1
+	}
+
+	return 0
+}
@@ -0,0 +1,136 @@
+package clusters
+
+import (
+	"math/rand"
+)
+
+type silhouetteEstimator struct {
+	min, max, sample int
+
+	factory ClustererFactory
+
+	distance DistanceFunc
+
+	// mean silhouette coefficients of every examined number of clusters
+	s []float64
+}
+
+// Implementation of cluster number estimator choosing the number of clusters with the highest mean silhouette coefficient
+// ("Silhouettes: a graphical aid to the interpretation and validation of cluster analysis", Rousseeuw). The clusterer created
+// by the factory is run for every number of clusters between min and max. If sample is greater than 0, the coefficient
+// is computed only for that many randomly chosen points, which is useful for large datasets. Partitions with fewer than 2 clusters
+// receive the lowest possible score of -1.
+func SilhouetteEstimator(min, max, sample int, factory ClustererFactory, distance DistanceFunc) (ScoringEstimator, error) {
+	if min < 2 {
+		return nil, errOneCluster
+	}
+
+	if max < min {
+		return nil, errInvalidRange
+	}
+
+	if sample < 0 {
+		return nil, errInvalidSample
+	}
+
+	if factory == nil {
+		return nil, errNilFactory
+	}
+
+	var d DistanceFunc
+	{
+		if distance != nil {
+			d = distance
+		} else {
+			d = EuclideanDistance
+		}
+	}
+
+	return &silhouetteEstimator{
+		min:      min,
+		max:      max,
+		sample:   sample,
+		factory:  factory,
+		distance: d,
+	}, nil
+}
+
+func (c *silhouetteEstimator) Estimate(data [][]float64) (int, error) {
+	if len(data) == 0 {
+		return 0, errEmptySet
+	}
+
+	var (
+		estimated = c.min
+		points    = c.points(len(data))
+	)
+
+	c.s = make([]float64, c.max-c.min+1)
+
+	for i := c.min; i <= c.max; i++ {
+		h, e := c.factory(i)
+		if e != nil {
+			return 0, e
+		}
+
+		if e = h.Learn(data); e != nil {
+			return 0, e
+		}
+
+		c.s[i-c.min] = silhouette(data, h.Guesses(), points, c.distance)
+
+		if c.s[i-c.min] > c.s[estimated-c.min] {
+			estimated = i
+		}
+	}
+
+	return estimated, nil
+}
+
+func (c *silhouetteEstimator) Scores() []float64 {
+	return c.s
+}
+
+// private
+func (c *silhouetteEstimator) points(size int) []int {
+	if c.sample == 0 || c.sample >= size {
+		var r = make([]int, size)
+
+		for i := 0; i < size; i++ {
+			r[i] = i
+		}
+
+		return r
+	}
+
+	return rand.Perm(size)[:c.sample]
+}
+
+// Mean silhouette coefficient of given points, or -1 if there are fewer than 2 clusters
+func silhouette(data [][]float64, mapping, points []int, distance DistanceFunc) float64 {
+	var (
+		n, t float64
+		k, s = clusterSizes(mapping)
+		d    = make([]float64, len(s))
+		f    = func(i, j int) float64 {
+			return distance(data[i], data[j])
+		}
+	)
+
+	if len(s) < 2 {
+		return -1
+	}
+
+	for _, i := range points {
+		if mapping[i] != -1 {
+			t += pointSilhouette(mapping, i, k, s, f, d)
+			n++
+		}
+	}
+
+	if n == 0 {
+		return -1
+	}
+
+	return t / n
+}
@@ -0,0 +1,59 @@
+package clusters
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSilhouetteEstimator(t *testing.T) {
+	const (
+		C = 3
+		M = 6
+	)
+
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 600)
+	)
+
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{r.NormFloat64() + float64(i%C)*10, r.NormFloat64() + float64(i%C)*5}
+	}
+
+	for _, s := range []int{0, 100} {
+		c, e := SilhouetteEstimator(2, M, s, func(k int) (HardClusterer, error) {
+			return KMeans(1000, k, EuclideanDistance)
+		}, nil)
+		if e != nil {
+			t.Errorf("Error initializing silhouette estimator: %s\n", e.Error())
+		}
+
+		n, e := c.Estimate(d)
+		if e != nil {
+			t.Errorf("Error running test: %s\n", e.Error())
+		}
+
+		if n != C {
+			t.Errorf("Estimated number of clusters should be %d, it is %d\n", C, n)
+		}
+
+		if len(c.Scores()) != M-1 {
+			t.Errorf("Number of scores should be %d, it is %d\n", M-1, len(c.Scores()))
+		}
+	}
+}
+
+func TestSilhouette(t *testing.T) {
+	var (
+		d = [][]float64{{0}, {1}, {10}, {12}, {100}}
+		m = []int{1, 1, 2, 2, -1}
+		p = []int{0, 1, 2, 3, 4}
+	)
+
+	// a = 1 and b = 11 for the first point, a = 1 and b = 10 for the second and so on
+	var e = ((1 - 1/11.0) + (1 - 1/10.0) + (1 - 2/9.5) + (1 - 2/11.5)) / 4
+
+	if s := silhouette(d, m, p, EuclideanDistance); s < e-1e-9 || s > e+1e-9 {
+		t.Errorf("Silhouette should be %f, it is %f\n", e, s)
+	}
+}
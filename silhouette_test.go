@@ -0,0 +1,86 @@
+package clusters
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSilhouettes(t *testing.T) {
+	var (
+		d = [][]float64{{0}, {1}, {10}, {12}, {100}}
+		m = []int{1, 1, 2, 2, -1}
+		p = []float64{1 - 1/11.0, 1 - 1/10.0, 1 - 2/9.5, 1 - 2/11.5}
+	)
+
+	s, e := Silhouettes(d, m, nil, 2, 0)
+	if e != nil {
+		t.Errorf("Error computing silhouettes: %s\n", e.Error())
+	}
+
+	for i, v := range p {
+		if math.Abs(s.Points[i]-v) > 1e-9 {
+			t.Errorf("Silhouette of point %d should be %f, it is %f\n", i, v, s.Points[i])
+		}
+	}
+
+	if !math.IsNaN(s.Points[4]) {
+		t.Errorf("Silhouette of noise should be NaN, it is %f\n", s.Points[4])
+	}
+
+	if math.Abs(s.Clusters[1]-(p[0]+p[1])/2) > 1e-9 || math.Abs(s.Clusters[2]-(p[2]+p[3])/2) > 1e-9 {
+		t.Errorf("Silhouettes of clusters are invalid: %v\n", s.Clusters)
+	}
+
+	if math.Abs(s.Mean-silhouette(d, m, []int{0, 1, 2, 3, 4}, EuclideanDistance)) > 1e-9 {
+		t.Errorf("Mean silhouette is invalid: %f\n", s.Mean)
+	}
+}
+
+func TestSilhouettesFlagMisassignedPoint(t *testing.T) {
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 1000)
+		m = make([]int, len(d))
+	)
+
+	for i := 0; i < len(d); i++ {
+		m[i] = i%2 + 1
+		d[i] = []float64{r.NormFloat64() + float64(i%2)*10, r.NormFloat64()}
+	}
+
+	// point placed in the first cluster but labelled as a member of the second one
+	m[0] = 2
+
+	s, e := Silhouettes(d, m, EuclideanDistance, 0, 0)
+	if e != nil {
+		t.Errorf("Error computing silhouettes: %s\n", e.Error())
+	}
+
+	for i, v := range s.Points {
+		if (i == 0) != (v < 0) {
+			t.Errorf("Only the misassigned point should have negative silhouette, point %d has %f\n", i, v)
+		}
+	}
+}
+
+func TestSilhouettesMemoryBudget(t *testing.T) {
+	var (
+		d = make([][]float64, 100)
+		m = make([]int, len(d))
+	)
+
+	for i := 0; i < len(d); i++ {
+		m[i] = i%2 + 1
+		d[i] = []float64{float64(i)}
+	}
+
+	// 4950 distances take 39600 bytes
+	if _, e := Silhouettes(d, m, nil, 0, 39599); e != errMemoryBudget {
+		t.Errorf("Silhouettes should not exceed the memory budget\n")
+	}
+
+	if _, e := Silhouettes(d, m, nil, 0, 39600); e != nil {
+		t.Errorf("Error computing silhouettes: %s\n", e.Error())
+	}
+}
@@ -0,0 +1,210 @@
+package clusters
+
+import (
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+const (
+	// number of bootstrap resamples of agreements used to compute confidence intervals of their mean
+	stabilityBootstraps = 1000
+)
+
+// AgreementMeasure denotes the measure of agreement between clusterings of overlapping resamples of the dataset
+type AgreementMeasure int
+
+const (
+	// ARIAgreement measures agreement with AdjustedRandIndex
+	ARIAgreement AgreementMeasure = iota
+	// JaccardAgreement measures agreement with the Jaccard coefficient of pairs of points clustered together
+	JaccardAgreement
+)
+
+type stabilityEstimator struct {
+	min, max, resamples int
+	seed                int64
+
+	measure AgreementMeasure
+
+	factory ClustererFactory
+
+	// source of resamples independent of reseeding done by clusterers
+	rand *rand.Rand
+
+	// mean agreement and its confidence interval for every examined number of clusters
+	s []float64
+	i [][2]float64
+}
+
+// Implementation of cluster number estimator choosing the number of clusters with the most stable clustering
+// ("Stability-Based Validation of Clustering Solutions", Lange et al.). For every number of clusters between min and max,
+// pairs of bootstrap resamples of the dataset are clustered by the clusterer created by the factory and agreement of
+// the clusterings on points drawn into both resamples is measured. Scores returns mean agreement, while Intervals
+// returns its 95% confidence intervals, computed by bootstrapping agreements of the resamples. Resamples are drawn from
+// a source seeded with seed, so the estimate is reproducible as long as the clusterers are.
+func StabilityEstimator(min, max, resamples int, seed int64, measure AgreementMeasure, factory ClustererFactory) (IntervalEstimator, error) {
+	if min < 2 {
+		return nil, errOneCluster
+	}
+
+	if max < min {
+		return nil, errInvalidRange
+	}
+
+	if resamples < 2 {
+		return nil, errInvalidResamples
+	}
+
+	if measure != ARIAgreement && measure != JaccardAgreement {
+		return nil, errInvalidMeasure
+	}
+
+	if factory == nil {
+		return nil, errNilFactory
+	}
+
+	return &stabilityEstimator{
+		min:       min,
+		max:       max,
+		resamples: resamples,
+		seed:      seed,
+		measure:   measure,
+		factory:   factory,
+	}, nil
+}
+
+func (c *stabilityEstimator) Estimate(data [][]float64) (int, error) {
+	if len(data) == 0 {
+		return 0, errEmptySet
+	}
+
+	var (
+		estimated = c.min
+		a         = make([]float64, c.resamples)
+		m         = make([]float64, stabilityBootstraps)
+	)
+
+	c.s = make([]float64, c.max-c.min+1)
+	c.i = make([][2]float64, c.max-c.min+1)
+	c.rand = rand.New(rand.NewSource(c.seed))
+
+	for k := c.min; k <= c.max; k++ {
+		for r := 0; r < c.resamples; r++ {
+			v, e := c.agreement(data, k)
+			if e != nil {
+				return 0, e
+			}
+
+			a[r] = v
+		}
+
+		c.s[k-c.min] = stat.Mean(a, nil)
+
+		// percentile interval of means of resampled agreements
+		for b := 0; b < len(m); b++ {
+			var t float64
+
+			for r := 0; r < len(a); r++ {
+				t += a[c.rand.Intn(len(a))]
+			}
+
+			m[b] = t / float64(len(a))
+		}
+
+		sort.Float64s(m)
+
+		c.i[k-c.min] = [2]float64{
+			stat.Quantile(0.025, stat.Empirical, m, nil),
+			stat.Quantile(0.975, stat.Empirical, m, nil),
+		}
+
+		if c.s[k-c.min] > c.s[estimated-c.min] {
+			estimated = k
+		}
+	}
+
+	return estimated, nil
+}
+
+// Mean agreement of every number of clusters, starting with the lowest one
+func (c *stabilityEstimator) Scores() []float64 {
+	return c.s
+}
+
+func (c *stabilityEstimator) Intervals() [][2]float64 {
+	return c.i
+}
+
+// private
+
+// Agreement of clusterings of two bootstrap resamples on the points they share
+func (c *stabilityEstimator) agreement(data [][]float64, k int) (float64, error) {
+	x, e := c.cluster(data, k)
+	if e != nil {
+		return 0, e
+	}
+
+	y, e := c.cluster(data, k)
+	if e != nil {
+		return 0, e
+	}
+
+	var a, b []int
+
+	for i, l := range x {
+		if m, ok := y[i]; ok {
+			a = append(a, l)
+			b = append(b, m)
+		}
+	}
+
+	if c.measure == ARIAgreement {
+		return AdjustedRandIndex(a, b)
+	}
+
+	t, e := ContingencyTable(a, b)
+	if e != nil {
+		return 0, e
+	}
+
+	var p, q, r = t.pairs()
+
+	if p+q-r == 0 {
+		return 1, nil
+	}
+
+	return r / (p + q - r), nil
+}
+
+// Clusters a bootstrap resample and returns labels of the drawn points by their indices in the dataset
+func (c *stabilityEstimator) cluster(data [][]float64, k int) (map[int]int, error) {
+	var (
+		s = make([][]float64, len(data))
+		n = make([]int, len(data))
+		r = make(map[int]int)
+	)
+
+	for i := 0; i < len(data); i++ {
+		n[i] = c.rand.Intn(len(data))
+		s[i] = data[n[i]]
+	}
+
+	h, e := c.factory(k)
+	if e != nil {
+		return nil, e
+	}
+
+	if e = h.Learn(s); e != nil {
+		return nil, e
+	}
+
+	for i, l := range h.Guesses() {
+		if _, ok := r[n[i]]; !ok {
+			r[n[i]] = l
+		}
+	}
+
+	return r, nil
+}
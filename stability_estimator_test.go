@@ -0,0 +1,93 @@
+package clusters
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestStabilityEstimator(t *testing.T) {
+	const (
+		C = 3
+		M = 5
+	)
+
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 300)
+		m = [][]float64{{0, 0}, {20, 0}, {10, 17}}
+	)
+
+	// clusters in the corners of an equilateral triangle, so no two of them are merged consistently
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{r.NormFloat64() + m[i%C][0], r.NormFloat64() + m[i%C][1]}
+	}
+
+	for _, a := range []AgreementMeasure{ARIAgreement, JaccardAgreement} {
+		c, e := StabilityEstimator(2, M, 20, 1, a, func(k int) (HardClusterer, error) {
+			return KMeans(1000, k, EuclideanDistance)
+		})
+		if e != nil {
+			t.Errorf("Error initializing stability estimator: %s\n", e.Error())
+		}
+
+		n, e := c.Estimate(d)
+		if e != nil {
+			t.Errorf("Error running test: %s\n", e.Error())
+		}
+
+		if n != C {
+			t.Errorf("Estimated number of clusters should be %d, it is %d %v\n", C, n, c.Scores())
+		}
+
+		for i, v := range c.Intervals() {
+			if v[0] > c.Scores()[i] || v[1] < c.Scores()[i] {
+				t.Errorf("Mean agreement %f is outside its interval %v\n", c.Scores()[i], v)
+			}
+		}
+	}
+}
+
+func TestStabilityEstimatorIntervals(t *testing.T) {
+	var (
+		r = rand.New(rand.NewSource(1))
+		d = make([][]float64, 300)
+		w = make([]float64, 0)
+	)
+
+	// touching clusters, so that agreement of clusterings varies between resamples
+	for i := 0; i < len(d); i++ {
+		d[i] = []float64{r.NormFloat64() + float64(i%2)*3, r.NormFloat64()}
+	}
+
+	for _, n := range []int{10, 40, 160} {
+		var is [][2]float64
+
+		for j := 0; j < 2; j++ {
+			c, e := StabilityEstimator(2, 2, n, 1, ARIAgreement, func(k int) (HardClusterer, error) {
+				return DBSCAN(5, 0.5, 1, nil)
+			})
+			if e != nil {
+				t.Errorf("Error initializing stability estimator: %s\n", e.Error())
+			}
+
+			if _, e = c.Estimate(d); e != nil {
+				t.Errorf("Error running test: %s\n", e.Error())
+			}
+
+			if j > 0 && c.Intervals()[0] != is[0] {
+				t.Errorf("Intervals with the same seed differ: %v vs %v\n", c.Intervals()[0], is[0])
+			}
+
+			is = c.Intervals()
+		}
+
+		w = append(w, is[0][1]-is[0][0])
+	}
+
+	// the interval narrows with the square root of the number of resamples, so it about halves every time
+	for i := 1; i < len(w); i++ {
+		if w[i] < w[i-1]*0.3 || w[i] > w[i-1]*0.7 {
+			t.Errorf("Interval should halve as resamples quadruple: %v\n", w)
+		}
+	}
+}
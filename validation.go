@@ -0,0 +1,217 @@
+package clusters
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// means and sizes of clusters of a partition, with noise (-1) excluded
+type partition struct {
+	// cluster labels mapped to consecutive numbers
+	k map[int]int
+
+	// means and sizes of clusters
+	m [][]float64
+	s []int
+
+	// mean of all points but noise and their number
+	c []float64
+	n int
+}
+
+// CalinskiHarabasz returns the Calinski-Harabasz index (variance ratio criterion) of the partition of the dataset given
+// by the mapping, e.g. Guesses of a clusterer. Higher values denote better separated clusters. Noise (-1) is excluded.
+func CalinskiHarabasz(data [][]float64, mapping []int) (float64, error) {
+	p, e := newPartition(data, mapping)
+	if e != nil {
+		return 0, e
+	}
+
+	if p.n == len(p.m) {
+		return 0, errNotEnoughData
+	}
+
+	w, b := p.withinSS(data, mapping), p.betweenSS()
+
+	if w == 0 {
+		return math.Inf(1), nil
+	}
+
+	return (b / float64(len(p.m)-1)) / (w / float64(p.n-len(p.m))), nil
+}
+
+// DaviesBouldin returns the Davies-Bouldin index of the partition of the dataset given by the mapping. Lower values
+// denote more compact and better separated clusters. Noise (-1) is excluded.
+func DaviesBouldin(data [][]float64, mapping []int) (float64, error) {
+	p, e := newPartition(data, mapping)
+	if e != nil {
+		return 0, e
+	}
+
+	var (
+		r float64
+		s = make([]float64, len(p.m))
+	)
+
+	// mean distance of members to the mean of their cluster
+	for i := 0; i < len(data); i++ {
+		if mapping[i] != -1 {
+			s[p.k[mapping[i]]] += EuclideanDistance(data[i], p.m[p.k[mapping[i]]])
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		s[i] /= float64(p.s[i])
+	}
+
+	for i := 0; i < len(p.m); i++ {
+		var m float64
+
+		for j := 0; j < len(p.m); j++ {
+			if i != j {
+				m = math.Max(m, (s[i]+s[j])/EuclideanDistance(p.m[i], p.m[j]))
+			}
+		}
+
+		r += m
+	}
+
+	return r / float64(len(p.m)), nil
+}
+
+// Dunn returns the Dunn index of the partition of the dataset given by the mapping, that is the ratio of the smallest
+// distance between points of different clusters to the largest diameter of a cluster. Higher values denote more compact
+// and better separated clusters. Noise (-1) is excluded. Pass nil to use EuclideanDistance.
+func Dunn(data [][]float64, mapping []int, distance DistanceFunc) (float64, error) {
+	if _, e := newPartition(data, mapping); e != nil {
+		return 0, e
+	}
+
+	var (
+		d      float64
+		lo, hi = math.Inf(1), 0.0
+		f      = distanceOrDefault(distance)
+	)
+
+	for i := 0; i < len(data); i++ {
+		if mapping[i] == -1 {
+			continue
+		}
+
+		for j := i + 1; j < len(data); j++ {
+			if mapping[j] == -1 {
+				continue
+			}
+
+			d = f(data[i], data[j])
+
+			if mapping[i] == mapping[j] {
+				hi = math.Max(hi, d)
+			} else {
+				lo = math.Min(lo, d)
+			}
+		}
+	}
+
+	if hi == 0 {
+		return math.Inf(1), nil
+	}
+
+	return lo / hi, nil
+}
+
+// SumOfSquares returns the within-cluster and between-cluster sums of squared euclidean distances of the partition
+// of the dataset given by the mapping. Noise (-1) is excluded.
+func SumOfSquares(data [][]float64, mapping []int) (within, between float64, err error) {
+	p, e := newPartition(data, mapping)
+	if e != nil {
+		return 0, 0, e
+	}
+
+	return p.withinSS(data, mapping), p.betweenSS(), nil
+}
+
+// private
+func newPartition(data [][]float64, mapping []int) (*partition, error) {
+	if len(data) == 0 {
+		return nil, errEmptySet
+	}
+
+	if len(data) != len(mapping) {
+		return nil, errMismatchedLabels
+	}
+
+	var p = newMeans(data, mapping)
+
+	if len(p.m) < 2 {
+		return nil, errNotEnoughClusters
+	}
+
+	return p, nil
+}
+
+func newMeans(data [][]float64, mapping []int) *partition {
+	var p = &partition{
+		k: make(map[int]int),
+	}
+
+	for i := 0; i < len(data); i++ {
+		if mapping[i] == -1 {
+			continue
+		}
+
+		if _, ok := p.k[mapping[i]]; !ok {
+			p.k[mapping[i]] = len(p.m)
+			p.m = append(p.m, make([]float64, len(data[i])))
+			p.s = append(p.s, 0)
+		}
+
+		if p.c == nil {
+			p.c = make([]float64, len(data[i]))
+		}
+
+		floats.Add(p.m[p.k[mapping[i]]], data[i])
+		floats.Add(p.c, data[i])
+
+		p.s[p.k[mapping[i]]]++
+		p.n++
+	}
+
+	for i := 0; i < len(p.m); i++ {
+		floats.Scale(1/float64(p.s[i]), p.m[i])
+	}
+
+	if p.n > 0 {
+		floats.Scale(1/float64(p.n), p.c)
+	}
+
+	return p
+}
+
+func (p *partition) withinSS(data [][]float64, mapping []int) float64 {
+	var r float64
+
+	for i := 0; i < len(data); i++ {
+		if mapping[i] != -1 {
+			r += EuclideanDistanceSquared(data[i], p.m[p.k[mapping[i]]])
+		}
+	}
+
+	return r
+}
+
+func (p *partition) betweenSS() float64 {
+	var r float64
+
+	for i := 0; i < len(p.m); i++ {
+		r += float64(p.s[i]) * EuclideanDistanceSquared(p.m[i], p.c)
+	}
+
+	return r
+}
+
+// Sum of squared euclidean distances of points to means of their clusters. Noise (-1) is skipped
+func inertia(data [][]float64, mapping []int) float64 {
+	return newMeans(data, mapping).withinSS(data, mapping)
+}
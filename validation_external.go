@@ -0,0 +1,348 @@
+package clusters
+
+import (
+	"math"
+	"sort"
+)
+
+// Contingency holds the number of data points shared by every pair of true and predicted clusters
+type Contingency struct {
+	// Labels of true and predicted clusters in ascending order
+	Truth, Predicted []int
+
+	// Counts[i][j] is the number of data points in the i-th true and the j-th predicted cluster
+	Counts [][]int
+}
+
+// ContingencyTable returns the contingency table of true labels and predicted ones, e.g. Guesses of a clusterer.
+// In all the external validation metrics noise (-1) is treated as a cluster of its own.
+func ContingencyTable(truth, predicted []int) (*Contingency, error) {
+	if len(truth) == 0 {
+		return nil, errEmptySet
+	}
+
+	if len(truth) != len(predicted) {
+		return nil, errMismatchedLabels
+	}
+
+	var (
+		a, b = labels(truth), labels(predicted)
+		c    = &Contingency{
+			Truth:     make([]int, len(a)),
+			Predicted: make([]int, len(b)),
+			Counts:    make([][]int, len(a)),
+		}
+	)
+
+	for l, i := range a {
+		c.Truth[i] = l
+	}
+
+	for l, j := range b {
+		c.Predicted[j] = l
+	}
+
+	for i := range c.Counts {
+		c.Counts[i] = make([]int, len(b))
+	}
+
+	for i := 0; i < len(truth); i++ {
+		c.Counts[a[truth[i]]][b[predicted[i]]]++
+	}
+
+	return c, nil
+}
+
+// AdjustedRandIndex returns the Rand index of the predicted labels adjusted for chance, ranging up to 1 for
+// identical partitions, with random ones scoring close to 0
+func AdjustedRandIndex(truth, predicted []int) (float64, error) {
+	c, e := ContingencyTable(truth, predicted)
+	if e != nil {
+		return 0, e
+	}
+
+	// a single point has no pairs
+	if len(truth) < 2 {
+		return 1, nil
+	}
+
+	var (
+		x, y, z = c.pairs()
+		d       = x * y / pairs(len(truth))
+		m       = (x + y) / 2
+	)
+
+	if m == d {
+		return 1, nil
+	}
+
+	return (z - d) / (m - d), nil
+}
+
+// NormalizedMutualInformation returns the mutual information of the labels divided by the arithmetic mean of their entropies
+func NormalizedMutualInformation(truth, predicted []int) (float64, error) {
+	c, e := ContingencyTable(truth, predicted)
+	if e != nil {
+		return 0, e
+	}
+
+	var (
+		hu, hv = c.entropies()
+		m      = (hu + hv) / 2
+	)
+
+	if m == 0 {
+		return 1, nil
+	}
+
+	return c.mutualInformation() / m, nil
+}
+
+// AdjustedMutualInformation returns the mutual information of the labels adjusted for chance
+// ("Information Theoretic Measures for Clusterings Comparison", Vinh et al.), normalized by the arithmetic mean of their entropies
+func AdjustedMutualInformation(truth, predicted []int) (float64, error) {
+	c, e := ContingencyTable(truth, predicted)
+	if e != nil {
+		return 0, e
+	}
+
+	var (
+		hu, hv = c.entropies()
+		x      = c.expectedMutualInformation()
+		d      = (hu+hv)/2 - x
+	)
+
+	if d == 0 {
+		return 1, nil
+	}
+
+	return (c.mutualInformation() - x) / d, nil
+}
+
+// Homogeneity returns 1 if every predicted cluster contains only members of a single true cluster
+// ("V-Measure: A conditional entropy-based external cluster evaluation measure", Rosenberg et al.)
+func Homogeneity(truth, predicted []int) (float64, error) {
+	h, _, _, e := homogeneityCompleteness(truth, predicted)
+
+	return h, e
+}
+
+// Completeness returns 1 if all members of every true cluster are assigned to the same predicted cluster
+func Completeness(truth, predicted []int) (float64, error) {
+	_, c, _, e := homogeneityCompleteness(truth, predicted)
+
+	return c, e
+}
+
+// VMeasure returns the harmonic mean of homogeneity and completeness
+func VMeasure(truth, predicted []int) (float64, error) {
+	_, _, v, e := homogeneityCompleteness(truth, predicted)
+
+	return v, e
+}
+
+// FowlkesMallows returns the geometric mean of precision and recall of pairs of data points clustered together
+func FowlkesMallows(truth, predicted []int) (float64, error) {
+	c, e := ContingencyTable(truth, predicted)
+	if e != nil {
+		return 0, e
+	}
+
+	var x, y, z = c.pairs()
+
+	if x == 0 || y == 0 {
+		return 0, nil
+	}
+
+	return z / math.Sqrt(x*y), nil
+}
+
+// Purity returns the fraction of data points belonging to the most common true cluster of their predicted cluster
+func Purity(truth, predicted []int) (float64, error) {
+	c, e := ContingencyTable(truth, predicted)
+	if e != nil {
+		return 0, e
+	}
+
+	var s int
+
+	for j := range c.Predicted {
+		var m int
+
+		for i := range c.Truth {
+			if c.Counts[i][j] > m {
+				m = c.Counts[i][j]
+			}
+		}
+
+		s += m
+	}
+
+	return float64(s) / float64(len(truth)), nil
+}
+
+// private
+func labels(mapping []int) map[int]int {
+	var (
+		r = make(map[int]int)
+		l = make([]int, 0)
+	)
+
+	for _, v := range mapping {
+		if _, ok := r[v]; !ok {
+			r[v] = 0
+			l = append(l, v)
+		}
+	}
+
+	sort.Ints(l)
+
+	for i, v := range l {
+		r[v] = i
+	}
+
+	return r
+}
+
+func pairs(n int) float64 {
+	return float64(n) * float64(n-1) / 2
+}
+
+// sizes of true and predicted clusters
+func (c *Contingency) sums() (a, b []int, n int) {
+	a, b = make([]int, len(c.Truth)), make([]int, len(c.Predicted))
+
+	for i := range c.Truth {
+		for j := range c.Predicted {
+			a[i] += c.Counts[i][j]
+			b[j] += c.Counts[i][j]
+			n += c.Counts[i][j]
+		}
+	}
+
+	return a, b, n
+}
+
+// numbers of pairs of data points sharing a true cluster, a predicted cluster and both
+func (c *Contingency) pairs() (x, y, z float64) {
+	var a, b, _ = c.sums()
+
+	for i := range a {
+		x += pairs(a[i])
+	}
+
+	for j := range b {
+		y += pairs(b[j])
+	}
+
+	for i := range c.Counts {
+		for j := range c.Counts[i] {
+			z += pairs(c.Counts[i][j])
+		}
+	}
+
+	return x, y, z
+}
+
+func (c *Contingency) entropies() (hu, hv float64) {
+	var a, b, n = c.sums()
+
+	return entropy(a, n), entropy(b, n)
+}
+
+func entropy(s []int, n int) float64 {
+	var h float64
+
+	for _, v := range s {
+		if v > 0 {
+			p := float64(v) / float64(n)
+			h -= p * math.Log(p)
+		}
+	}
+
+	return h
+}
+
+func (c *Contingency) mutualInformation() float64 {
+	var (
+		r       float64
+		a, b, n = c.sums()
+	)
+
+	for i := range c.Counts {
+		for j, v := range c.Counts[i] {
+			if v > 0 {
+				r += float64(v) / float64(n) * math.Log(float64(n)*float64(v)/(float64(a[i])*float64(b[j])))
+			}
+		}
+	}
+
+	return r
+}
+
+// expected mutual information of random partitions with the same cluster sizes under the hypergeometric model
+func (c *Contingency) expectedMutualInformation() float64 {
+	var (
+		r       float64
+		a, b, n = c.sums()
+		f       = float64(n)
+		lg      = func(x int) float64 {
+			v, _ := math.Lgamma(float64(x) + 1)
+			return v
+		}
+	)
+
+	for i := range a {
+		for j := range b {
+			lo := a[i] + b[j] - n
+			if lo < 1 {
+				lo = 1
+			}
+
+			hi := a[i]
+			if b[j] < hi {
+				hi = b[j]
+			}
+
+			for k := lo; k <= hi; k++ {
+				v := float64(k)
+
+				p := lg(a[i]) + lg(b[j]) + lg(n-a[i]) + lg(n-b[j]) -
+					lg(n) - lg(k) - lg(a[i]-k) - lg(b[j]-k) - lg(n-a[i]-b[j]+k)
+
+				r += v / f * math.Log(f*v/(float64(a[i])*float64(b[j]))) * math.Exp(p)
+			}
+		}
+	}
+
+	return r
+}
+
+func homogeneityCompleteness(truth, predicted []int) (h, c, v float64, e error) {
+	t, e := ContingencyTable(truth, predicted)
+	if e != nil {
+		return 0, 0, 0, e
+	}
+
+	var (
+		hu, hv = t.entropies()
+		mi     = t.mutualInformation()
+	)
+
+	// H(C|K) = H(C) - I(C, K)
+	h, c = 1, 1
+
+	if hu > 0 {
+		h = mi / hu
+	}
+
+	if hv > 0 {
+		c = mi / hv
+	}
+
+	if h+c > 0 {
+		v = 2 * h * c / (h + c)
+	}
+
+	return h, c, v, nil
+}
@@ -0,0 +1,79 @@
+package clusters
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExternalValidation(t *testing.T) {
+	var (
+		a = []int{1, 1, 2, 2}
+		b = []int{1, 1, 2, 3}
+	)
+
+	for _, m := range []struct {
+		name string
+		f    func([]int, []int) (float64, error)
+		v    float64
+	}{
+		{"AdjustedRandIndex", AdjustedRandIndex, 4.0 / 7},
+		{"NormalizedMutualInformation", NormalizedMutualInformation, 0.8},
+		{"AdjustedMutualInformation", AdjustedMutualInformation, 4.0 / 7},
+		{"Homogeneity", Homogeneity, 1},
+		{"Completeness", Completeness, 2.0 / 3},
+		{"VMeasure", VMeasure, 0.8},
+		{"FowlkesMallows", FowlkesMallows, 1 / math.Sqrt(2)},
+		{"Purity", Purity, 1},
+	} {
+		v, e := m.f(a, b)
+		if e != nil {
+			t.Errorf("Error computing %s: %s\n", m.name, e.Error())
+		}
+
+		if math.Abs(v-m.v) > 1e-9 {
+			t.Errorf("%s should be %f, it is %f\n", m.name, m.v, v)
+		}
+
+		if v, _ = m.f(a, a); math.Abs(v-1) > 1e-9 {
+			t.Errorf("%s of identical labels should be 1, it is %f\n", m.name, v)
+		}
+	}
+
+	if v, _ := AdjustedRandIndex(a[:1], b[:1]); v != 1 {
+		t.Errorf("AdjustedRandIndex of a single point should be 1, it is %f\n", v)
+	}
+}
+
+func TestExternalValidationWithNoise(t *testing.T) {
+	var (
+		a = []int{1, 1, 1, 2, 2, 2, -1, 3}
+		b = []int{1, 1, 2, 2, 3, 3, -1, -1}
+	)
+
+	v, e := AdjustedMutualInformation(a, b)
+	if e != nil {
+		t.Errorf("Error computing AdjustedMutualInformation: %s\n", e.Error())
+	}
+
+	// expected mutual information checked against the mean over all permutations of labels
+	if math.Abs(v-0.324647573400518) > 1e-9 {
+		t.Errorf("AdjustedMutualInformation should be %f, it is %f\n", 0.324647573400518, v)
+	}
+
+	if v, _ = Purity(a, b); v != 0.75 {
+		t.Errorf("Purity should be 0.75, it is %f\n", v)
+	}
+
+	c, e := ContingencyTable(a, b)
+	if e != nil {
+		t.Errorf("Error computing contingency table: %s\n", e.Error())
+	}
+
+	if c.Truth[0] != -1 || c.Predicted[0] != -1 || c.Counts[0][0] != 1 || c.Counts[3][0] != 1 {
+		t.Errorf("Contingency table is invalid: %v\n", c)
+	}
+
+	if _, e = AdjustedRandIndex(a, b[1:]); e == nil {
+		t.Error("Mismatched labels should result in an error\n")
+	}
+}
@@ -0,0 +1,61 @@
+package clusters
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInternalValidation(t *testing.T) {
+	var (
+		d = [][]float64{{0}, {2}, {10}, {12}, {100}}
+		m = []int{1, 1, 2, 2, -1}
+	)
+
+	w, b, e := SumOfSquares(d, m)
+	if e != nil {
+		t.Errorf("Error computing sums of squares: %s\n", e.Error())
+	}
+
+	if w != 4 || b != 100 {
+		t.Errorf("Sums of squares should be 4 and 100, they are %f and %f\n", w, b)
+	}
+
+	ch, e := CalinskiHarabasz(d, m)
+	if e != nil {
+		t.Errorf("Error computing Calinski-Harabasz index: %s\n", e.Error())
+	}
+
+	if math.Abs(ch-50) > 1e-9 {
+		t.Errorf("Calinski-Harabasz index should be 50, it is %f\n", ch)
+	}
+
+	db, e := DaviesBouldin(d, m)
+	if e != nil {
+		t.Errorf("Error computing Davies-Bouldin index: %s\n", e.Error())
+	}
+
+	if math.Abs(db-0.2) > 1e-9 {
+		t.Errorf("Davies-Bouldin index should be 0.2, it is %f\n", db)
+	}
+
+	dn, e := Dunn(d, m, nil)
+	if e != nil {
+		t.Errorf("Error computing Dunn index: %s\n", e.Error())
+	}
+
+	if math.Abs(dn-4) > 1e-9 {
+		t.Errorf("Dunn index should be 4, it is %f\n", dn)
+	}
+}
+
+func TestInternalValidationErrors(t *testing.T) {
+	var d = [][]float64{{0}, {2}, {10}}
+
+	if _, e := CalinskiHarabasz(d, []int{1, 1}); e == nil {
+		t.Error("Mismatched labels should result in an error\n")
+	}
+
+	if _, e := DaviesBouldin(d, []int{1, 1, -1}); e == nil {
+		t.Error("Single cluster should result in an error\n")
+	}
+}